@@ -25,6 +25,7 @@ package logrus
 import (
 	"fmt"
 	"os"
+	"sync"
 )
 
 var handlers = []func(){}
@@ -45,10 +46,13 @@ func runHandlers() {
 	}
 }
 
+// osExit terminates the program in Exit, tests replace it to observe Fatal.
+var osExit = os.Exit
+
 // Exit runs all the Logrus atexit handlers and then terminates the program using os.Exit(code)
 func Exit(code int) {
 	runHandlers()
-	os.Exit(code)
+	osExit(code)
 }
 
 // RegisterExitHandler adds a Logrus Exit handler, call logrus.Exit to invoke
@@ -62,3 +66,51 @@ func Exit(code int) {
 func RegisterExitHandler(handler func()) {
 	handlers = append(handlers, handler)
 }
+
+// exitWriters are the writers flushed by the exit handlers until they are
+// closed, registered once by a single handler so that the writers created and
+// closed by the program are not kept forever.
+var (
+	exitWritersMu sync.Mutex
+	exitWriters   []exitWriter
+)
+
+type exitWriter struct {
+	writer interface{}
+	flush  func()
+}
+
+func init() {
+	RegisterExitHandler(flushExitWriters)
+}
+
+// registerExitWriter has flush called for writer by the exit handlers until
+// unregisterExitWriter is called for it.
+func registerExitWriter(writer interface{}, flush func()) {
+	exitWritersMu.Lock()
+	defer exitWritersMu.Unlock()
+	exitWriters = append(exitWriters, exitWriter{writer, flush})
+}
+
+func unregisterExitWriter(writer interface{}) {
+	exitWritersMu.Lock()
+	defer exitWritersMu.Unlock()
+	for i, w := range exitWriters {
+		if w.writer == writer {
+			exitWriters = append(exitWriters[:i], exitWriters[i+1:]...)
+			return
+		}
+	}
+}
+
+// flushExitWriters flushes the writers the last registered first, a writer
+// wrapping another one is created after it and must be flushed before it.
+func flushExitWriters() {
+	exitWritersMu.Lock()
+	writers := make([]exitWriter, len(exitWriters))
+	copy(writers, exitWriters)
+	exitWritersMu.Unlock()
+	for i := len(writers) - 1; i >= 0; i-- {
+		runHandler(writers[i].flush)
+	}
+}
@@ -0,0 +1,33 @@
+package logrus
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yyscamper/go-spew/spew"
+)
+
+// SetArgJoiner sets the function building the message from the arguments of
+// Info, Error and the other functions of their family, which dump them with
+// spew by default. Passing nil restores the default, SprintArgs is a less
+// verbose alternative.
+func (logger *Logger) SetArgJoiner(joiner func(args []interface{}) string) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.ArgJoiner = joiner
+}
+
+// SprintArgs joins the arguments like fmt.Sprint, except for maps, slices and
+// structs, which are still dumped with spew, e.g. `Info("retry ", 3)` logs
+// `retry 3`. It is meant to be set with SetArgJoiner.
+func SprintArgs(args []interface{}) string {
+	parts := make([]interface{}, len(args))
+	for i, arg := range args {
+		if isComplexValue(arg) {
+			parts[i] = strings.TrimSuffix(spew.Sdump(arg), "\n")
+		} else {
+			parts[i] = arg
+		}
+	}
+	return fmt.Sprint(parts...)
+}
@@ -0,0 +1,37 @@
+package logrus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yyscamper/go-spew/spew"
+)
+
+func TestArgJoiner(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Info("a", 1, "b")
+	}, func(fields Fields) {
+		assert.Equal(t, spew.Sdump("a", 1, "b"), fields["msg"], "the arguments should be dumped by default")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetArgJoiner(SprintArgs)
+		log.Info("a", 1, "b")
+	}, func(fields Fields) {
+		assert.Equal(t, "a1b", fields["msg"])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetArgJoiner(func(args []interface{}) string { return "joined" })
+		log.Error("a", 1, "b")
+	}, func(fields Fields) {
+		assert.Equal(t, "joined", fields["msg"])
+	})
+}
+
+func TestSprintArgsDumpsComplexValues(t *testing.T) {
+	value := map[string]int{"a": 1}
+	msg := SprintArgs([]interface{}{"value: ", value})
+	assert.Equal(t, "value: "+strings.TrimSuffix(spew.Sdump(value), "\n"), msg)
+}
@@ -0,0 +1,28 @@
+package logrus
+
+// Assert logs msg at ErrorLevel when cond is false, or panics with it like
+// Panic when AssertPanics is set, e.g. in development builds:
+//
+//    log.WithField("id", id).Assert(len(items) > 0, "no items left")
+//
+// Nothing is done, or formatted, when cond is true. The entry is returned so
+// calls can be chained.
+func (entry *Entry) Assert(cond bool, msg string) *Entry {
+	if cond {
+		return entry
+	}
+	if entry.Logger.AssertPanics {
+		entry.Panic(msg)
+	} else {
+		entry.Error(msg)
+	}
+	return entry
+}
+
+// SetAssertPanics makes the failed assertions panic instead of being logged at
+// ErrorLevel, see Entry.Assert.
+func (logger *Logger) SetAssertPanics(enable bool) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.AssertPanics = enable
+}
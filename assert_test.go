@@ -0,0 +1,34 @@
+package logrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssert(t *testing.T) {
+	for _, panics := range []bool{false, true} {
+		var buffer bytes.Buffer
+		var fired int
+		logger := New()
+		logger.Out = &buffer
+		logger.SetAssertPanics(panics)
+		logger.Hooks.Add(&hookFunc{fire: func(*Entry) { fired++ }})
+
+		entry := logger.WithField("id", 1)
+		assert.Equal(t, entry, entry.Assert(true, "ok"))
+		assert.Equal(t, "", buffer.String(), "a true condition should log nothing")
+		assert.Equal(t, 0, fired)
+
+		if panics {
+			assert.Panics(t, func() { entry.Assert(false, "broken") })
+			assert.Contains(t, buffer.String(), "level=panic")
+		} else {
+			assert.Equal(t, entry, entry.Assert(false, "broken"))
+			assert.Contains(t, buffer.String(), "level=error")
+		}
+		assert.Contains(t, buffer.String(), "msg=broken")
+		assert.Contains(t, buffer.String(), "id=1")
+	}
+}
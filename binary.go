@@ -0,0 +1,82 @@
+package logrus
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// BinaryEncoding is the way the bytes added with WithBinary are formatted.
+type BinaryEncoding int
+
+const (
+	// BinaryHex formats the bytes as lowercase hexadecimal, e.g. `0aff`.
+	BinaryHex BinaryEncoding = iota
+	// BinaryBase64 formats the bytes with the standard base64 encoding.
+	BinaryBase64
+	// BinaryLength only formats the number of bytes, e.g. `(42 bytes)`.
+	BinaryLength
+)
+
+// Binary is a byte slice field added with WithBinary, formatted the same way
+// by all the formatters, as a string in JSON. Bytes may be the beginning of
+// the data only, Length is the size of the whole data, the formatted value then
+// ends with TruncatedMarker and the size, e.g. `0aff...(1024 bytes)`.
+type Binary struct {
+	Bytes    []byte
+	Length   int
+	Encoding BinaryEncoding
+}
+
+func (b Binary) String() string {
+	var s string
+	switch b.Encoding {
+	case BinaryBase64:
+		s = base64.StdEncoding.EncodeToString(b.Bytes)
+	case BinaryLength:
+		return fmt.Sprintf("(%d bytes)", b.Length)
+	default:
+		s = hex.EncodeToString(b.Bytes)
+	}
+	if len(b.Bytes) < b.Length {
+		s += fmt.Sprintf("%s(%d bytes)", TruncatedMarker, b.Length)
+	}
+	return s
+}
+
+// MarshalJSON writes the formatted bytes as a JSON string.
+func (b Binary) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(b.String())), nil
+}
+
+// Add a byte slice field to the Entry, formatted with the given encoding
+// instead of the list of the byte values. Only the first MaxBinaryBytes bytes
+// are kept when the logger has a limit, see SetMaxBinaryBytes. The bytes are
+// copied, the slice can be reused once WithBinary returns.
+func (entry *Entry) WithBinary(key string, b []byte, enc BinaryEncoding) *Entry {
+	if entry.noop {
+		return entry
+	}
+	kept := b
+	if max := entry.Logger.MaxBinaryBytes; max > 0 && len(kept) > max {
+		kept = kept[:max]
+	}
+	if enc == BinaryLength {
+		kept = nil
+	}
+	return entry.withOne(key, Binary{
+		Bytes:    append([]byte(nil), kept...),
+		Length:   len(b),
+		Encoding: enc,
+	})
+}
+
+// SetMaxBinaryBytes limits the number of bytes of the WithBinary fields which
+// are formatted, the larger ones are truncated and their size is appended,
+// e.g. `0aff...(1024 bytes)`. 0 means no limit, the default.
+func (logger *Logger) SetMaxBinaryBytes(max int) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.MaxBinaryBytes = max
+}
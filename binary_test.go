@@ -0,0 +1,58 @@
+package logrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBinaryEncodings(t *testing.T) {
+	data := []byte{0x0a, 0xff, 0x00, 0x41}
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithField("x", 0).
+			WithBinary("hex", data, BinaryHex).
+			WithBinary("base64", data, BinaryBase64).
+			WithBinary("length", data, BinaryLength).
+			Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "0aff0041", fields["hex"])
+		assert.Equal(t, "Cv8AQQ==", fields["base64"])
+		assert.Equal(t, "(4 bytes)", fields["length"])
+	})
+
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	NewEntry(logger).WithBinary("hex", data, BinaryHex).Info("test")
+	assert.Equal(t, "level=info msg=test hex=0aff0041 \n", buffer.String())
+}
+
+func TestWithBinaryTruncated(t *testing.T) {
+	data := make([]byte, 100)
+	data[0] = 0xab
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetMaxBinaryBytes(2)
+		log.WithField("x", 0).
+			WithBinary("hex", data, BinaryHex).
+			WithBinary("base64", data, BinaryBase64).
+			WithBinary("short", data[:2], BinaryHex).
+			Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "ab00...(100 bytes)", fields["hex"])
+		assert.Equal(t, "qwA=...(100 bytes)", fields["base64"])
+		assert.Equal(t, "ab00", fields["short"])
+	})
+}
+
+func TestWithBinaryCopiesBytes(t *testing.T) {
+	data := []byte{1, 2}
+	entry := NewEntry(New()).WithBinary("b", data, BinaryHex)
+	data[0] = 0xff
+	assert.Equal(t, "0102", entry.Data["b"].(Binary).String())
+}
+
+func TestWithBinaryOnNoopEntry(t *testing.T) {
+	assert.NotPanics(t, func() { New().If(false).WithBinary("b", []byte{1}, BinaryHex).Info("test") })
+}
@@ -0,0 +1,45 @@
+package logrus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Boost sets the level of a module, or of the logger for DefaultModuleName,
+// until the returned function is called, which restores the previous level:
+//
+//    restore := logger.Boost("db", logrus.DebugLevel)
+//    defer restore()
+//
+// Boosts nest, each restore sets back the level in place when its Boost was
+// called, so they should be restored in the reverse order. Calling restore
+// more than once does nothing.
+//
+// Boosting DefaultModuleName sets the logger's Level, which the windows of the
+// LevelSchedule take precedence over: the boost has no effect while a window
+// applies, and restore sets back the Level, not the level of the window.
+func (logger *Logger) Boost(moduleName string, level Level) (restore func()) {
+	var once sync.Once
+	if moduleName == DefaultModuleName {
+		prev := Level(atomic.LoadUint32((*uint32)(&logger.Level)))
+		logger.setLevel(level)
+		return func() { once.Do(func() { logger.setLevel(prev) }) }
+	}
+
+	key := logger.moduleKey(moduleName)
+	logger.moduleLevelsMu.Lock()
+	prev, had := logger.ModuleLevels[key]
+	logger.ModuleLevels[key] = level
+	logger.moduleLevelsMu.Unlock()
+	return func() {
+		once.Do(func() {
+			logger.moduleLevelsMu.Lock()
+			defer logger.moduleLevelsMu.Unlock()
+			if had {
+				logger.ModuleLevels[key] = prev
+			} else {
+				delete(logger.ModuleLevels, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package logrus
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoostNested(t *testing.T) {
+	logger := New()
+	logger.SetModuleLevel("db", WarnLevel)
+
+	restoreDebug := logger.Boost("db", DebugLevel)
+	assert.Equal(t, DebugLevel, logger.level("db"))
+	restoreError := logger.Boost("db", ErrorLevel)
+	assert.Equal(t, ErrorLevel, logger.level("db"))
+
+	restoreError()
+	assert.Equal(t, DebugLevel, logger.level("db"))
+	restoreDebug()
+	assert.Equal(t, WarnLevel, logger.level("db"))
+
+	restoreDebug()
+	assert.Equal(t, WarnLevel, logger.level("db"), "restoring twice should do nothing")
+}
+
+func TestBoostModuleWithoutLevel(t *testing.T) {
+	logger := New()
+	logger.Level = WarnLevel
+
+	restore := logger.Boost("db", DebugLevel)
+	assert.Equal(t, DebugLevel, logger.level("db"))
+	restore()
+	_, ok := logger.ModuleLevels["db"]
+	assert.False(t, ok, "the module level should be removed")
+	assert.Equal(t, WarnLevel, logger.level("db"))
+}
+
+func TestBoostDefaultModule(t *testing.T) {
+	logger := New()
+	logger.Level = WarnLevel
+
+	restore := logger.Boost(DefaultModuleName, DebugLevel)
+	assert.Equal(t, DebugLevel, logger.level())
+	restore()
+	assert.Equal(t, WarnLevel, logger.level())
+}
+
+func TestBoostDefaultModuleWithSchedule(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	logger := New()
+	logger.Level = WarnLevel
+	logger.SetClock(func() time.Time { return now })
+	logger.SetLevelSchedule([]ScheduleEntry{{Start: 9 * time.Hour, End: 18 * time.Hour, Level: ErrorLevel}})
+
+	restore := logger.Boost(DefaultModuleName, DebugLevel)
+	assert.Equal(t, ErrorLevel, logger.level(), "the boost should have no effect during a window")
+	now = now.Add(12 * time.Hour)
+	assert.Equal(t, DebugLevel, logger.level(), "the boost should apply outside of the windows")
+
+	restore()
+	assert.Equal(t, WarnLevel, logger.Level, "restore should not set the level of the window")
+	now = now.Add(-12 * time.Hour)
+	restore = logger.Boost(DefaultModuleName, DebugLevel)
+	restore()
+	assert.Equal(t, WarnLevel, logger.Level, "restore should not set the level of the window")
+}
+
+func TestBoostConcurrentLogging(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	db := logger.NewModule("db")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			logger.Boost("db", DebugLevel)()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			db.Debug("test")
+		}
+	}()
+	wg.Wait()
+	assert.Equal(t, InfoLevel, logger.level("db"))
+}
@@ -0,0 +1,89 @@
+package logrus
+
+import "sync/atomic"
+
+// DefaultBufferedEntries is the number of entries kept by StartBuffering when
+// no maximum is given.
+const DefaultBufferedEntries = 1000
+
+// entryRing keeps the latest entries logged while buffering.
+type entryRing struct {
+	entries []*Entry
+	start   int
+	dropped int
+}
+
+func (r *entryRing) add(entry *Entry) {
+	if len(r.entries) < cap(r.entries) {
+		r.entries = append(r.entries, entry)
+		return
+	}
+	// full, overwrite the oldest
+	r.entries[r.start] = entry
+	r.start = (r.start + 1) % len(r.entries)
+	r.dropped++
+}
+
+func (r *entryRing) ordered() []*Entry {
+	return append(r.entries[r.start:len(r.entries):len(r.entries)], r.entries[:r.start]...)
+}
+
+// StartBuffering keeps the logged entries in memory, instead of firing the
+// hooks and writing them, until Replay is called. It is meant for the early
+// startup, before the output and the hooks are configured. At most max
+// entries are kept, DefaultBufferedEntries when max isn't positive, and the
+// oldest ones are dropped first. A fatal or panic entry replays the buffered
+// entries before being written.
+func (logger *Logger) StartBuffering(max int) {
+	if max <= 0 {
+		max = DefaultBufferedEntries
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.bootstrap = &entryRing{entries: make([]*Entry, 0, max)}
+	atomic.StoreInt32(&logger.buffering, 1)
+}
+
+// Replay stops buffering and emits the buffered entries in order, through the
+// hooks, formatter and output configured at this time. When entries were
+// dropped it first logs a warning with their number in the "dropped" field.
+func (logger *Logger) Replay() {
+	logger.mu.Lock()
+	ring := logger.bootstrap
+	logger.bootstrap = nil
+	atomic.StoreInt32(&logger.buffering, 0)
+	logger.mu.Unlock()
+	if ring == nil {
+		return
+	}
+
+	if ring.dropped > 0 {
+		logger.WithField("dropped", ring.dropped).Warnf("dropped %d entries buffered before Replay", ring.dropped)
+	}
+	for _, entry := range ring.ordered() {
+		entry.emit()
+	}
+}
+
+// buffer keeps the entry when the logger is buffering, it returns false
+// otherwise. Fatal and panic entries are never kept, the buffered entries are
+// replayed before them as the program is about to stop.
+func (logger *Logger) buffer(entry *Entry) bool {
+	if atomic.LoadInt32(&logger.buffering) == 0 {
+		return false
+	}
+	if entry.Level == FatalLevel || entry.Level == PanicLevel {
+		logger.Replay()
+		return false
+	}
+	// Data is already a copy owned by the entry, see log()
+	buffered := *entry
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if logger.bootstrap == nil {
+		return false
+	}
+	logger.bootstrap.add(&buffered)
+	return true
+}
@@ -0,0 +1,87 @@
+package logrus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type messagesHook struct {
+	messages []string
+}
+
+func (h *messagesHook) Levels() []Level { return AllLevels }
+
+func (h *messagesHook) Fire(entry *Entry) error {
+	h.messages = append(h.messages, entry.Message)
+	return nil
+}
+
+func TestReplayBufferedEntries(t *testing.T) {
+	var early, buffer bytes.Buffer
+	logger := New()
+	logger.Out = &early
+	logger.StartBuffering(10)
+
+	logger.Info("first")
+	logger.WithField("a", 1).Warn("second")
+	logger.Debug("filtered")
+
+	assert.Equal(t, "", early.String(), "nothing should be written while buffering")
+
+	hook := &messagesHook{}
+	logger.Out = &buffer
+	logger.Formatter = &JSONFormatter{DisableTimestamp: true}
+	logger.Hooks.Add(hook)
+	logger.Replay()
+	logger.Info("third")
+
+	assert.Equal(t, `{"level":"info","msg":"first"}
+{"a":1,"level":"warning","msg":"second"}
+{"level":"info","msg":"third"}
+`, buffer.String(), "buffered entries should be formatted with the formatter at replay")
+	assert.Equal(t, []string{"first", "second", "third"}, hook.messages)
+}
+
+func TestReplayDropsOldest(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Formatter = &TextFormatter{DisableTimestamp: true}
+	logger.StartBuffering(2)
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+	logger.Info("four")
+
+	logger.Out = &buffer
+	logger.Replay()
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Contains(t, lines[0], "dropped=2")
+		assert.Contains(t, lines[1], "msg=three")
+		assert.Contains(t, lines[2], "msg=four")
+	}
+
+	buffer.Reset()
+	logger.Replay()
+	assert.Equal(t, "", buffer.String(), "a second replay should do nothing")
+}
+
+func TestPanicReplaysBufferedEntries(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableTimestamp: true}
+	logger.StartBuffering(10)
+
+	logger.Info("early")
+	assert.Panics(t, func() { logger.Panic("boom") })
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], "msg=early")
+		assert.Contains(t, lines[1], "msg=boom")
+	}
+}
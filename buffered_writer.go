@@ -0,0 +1,117 @@
+package logrus
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is the size used by NewBufferedWriter when none is given.
+const DefaultBufferSize = 64 * 1024
+
+// BufferedWriter is an io.Writer collecting log entries in memory and writing
+// them to the underlying writer in batches, when the buffer is full or when
+// the flush interval elapses. Set it as `Logger.Out` to avoid a write syscall
+// per entry under heavy load:
+//
+//    logger.Out = logrus.NewBufferedWriter(file, 0, time.Second)
+//
+// Entries are written in the order they were logged. A writer created with
+// NewBufferedWriter is flushed by the exit handlers until it is closed, so
+// nothing is lost on Fatal or logrus.Exit, other ways of exiting should call
+// Flush or Close. Logger.Close closes it.
+type BufferedWriter struct {
+	out  io.Writer
+	size int
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+	// error of the last background flush, returned by the next Flush
+	err error
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBufferedWriter creates a BufferedWriter writing to out once size bytes
+// are buffered (DefaultBufferSize if size <= 0) and every interval, if the
+// interval is positive.
+func NewBufferedWriter(out io.Writer, size int, interval time.Duration) *BufferedWriter {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	w := &BufferedWriter{
+		out:  out,
+		size: size,
+		done: make(chan struct{}),
+	}
+	w.buf.Grow(size)
+	if interval > 0 {
+		go w.flushEvery(interval)
+	}
+	registerExitWriter(w, func() { w.Flush() })
+	return w
+}
+
+func (w *BufferedWriter) flushEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.mu.Lock()
+			if err := w.flush(); err != nil {
+				w.err = err
+			}
+			w.mu.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *BufferedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.buf.Len()+len(p) > w.size {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(p) >= w.size {
+		return w.out.Write(p)
+	}
+	return w.buf.Write(p)
+}
+
+// Flush writes the buffered entries to the underlying writer.
+func (w *BufferedWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.flush()
+	if err == nil {
+		err = w.err
+	}
+	w.err = nil
+	return err
+}
+
+func (w *BufferedWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.out.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// Close stops the background flushing and flushes the buffered entries. It
+// does not close the underlying writer.
+func (w *BufferedWriter) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		unregisterExitWriter(w)
+	})
+	return w.Flush()
+}
@@ -0,0 +1,110 @@
+package logrus
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer safe for use by the background flush.
+type syncBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes++
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestBufferedWriterFlush(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, 0, 0)
+	defer w.Close()
+
+	logger := New()
+	logger.Out = w
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+
+	for i := 0; i < 10; i++ {
+		logger.Infof("line %d", i)
+	}
+	assert.Equal(t, "", out.String(), "entries should be buffered")
+
+	assert.NoError(t, w.Flush())
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, 10, len(lines))
+	for i, line := range lines {
+		assert.Contains(t, line, fmt.Sprintf(`msg="line %d"`, i), "entries should keep their order")
+	}
+	assert.Equal(t, 1, out.writes, "entries should be written in a single batch")
+}
+
+func TestBufferedWriterFlushesWhenFull(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, 10, 0)
+	defer w.Close()
+
+	w.Write([]byte("12345"))
+	w.Write([]byte("67890"))
+	assert.Equal(t, "", out.String())
+	w.Write([]byte("abc"))
+	assert.Equal(t, "1234567890", out.String())
+	w.Write([]byte("larger than the buffer"))
+	assert.Equal(t, "1234567890abclarger than the buffer", out.String())
+}
+
+func TestBufferedWriterFlushesPeriodically(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, 0, 10*time.Millisecond)
+	defer w.Close()
+
+	w.Write([]byte("hello\n"))
+	deadline := time.Now().Add(time.Second)
+	for out.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, "hello\n", out.String())
+}
+
+func TestBufferedWriterClose(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, 0, time.Hour)
+
+	w.Write([]byte("hello\n"))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "hello\n", out.String())
+	assert.NoError(t, w.Close(), "closing twice should be safe")
+}
+
+func TestBufferedWriterFlushedOnExit(t *testing.T) {
+	out := &syncBuffer{}
+	w := NewBufferedWriter(out, 0, 0)
+	defer w.Close()
+
+	w.Write([]byte("hello\n"))
+	flushExitWriters()
+	assert.Equal(t, "hello\n", out.String())
+}
+
+func TestBufferedWriterUnregisteredOnClose(t *testing.T) {
+	current := len(exitWriters)
+	w := NewBufferedWriter(&syncBuffer{}, 0, 0)
+	assert.Len(t, exitWriters, current+1)
+	w.Close()
+	assert.Len(t, exitWriters, current, "a closed writer should not be kept by the exit handlers")
+}
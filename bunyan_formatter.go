@@ -0,0 +1,97 @@
+package logrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Bunyan log levels, see https://github.com/trentm/node-bunyan#levels
+const (
+	BunyanTraceLevel = 10
+	BunyanDebugLevel = 20
+	BunyanInfoLevel  = 30
+	BunyanWarnLevel  = 40
+	BunyanErrorLevel = 50
+	BunyanFatalLevel = 60
+)
+
+// bunyanTimestampFormat is the ISO 8601 layout (with milliseconds, in UTC)
+// written by node-bunyan.
+const bunyanTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// BunyanLevel returns the numeric bunyan level for a logrus level. Bunyan has
+// no panic level, PanicLevel maps to fatal.
+func BunyanLevel(level Level) int {
+	switch standardLevel(level) {
+	case TraceLevel:
+		return BunyanTraceLevel
+	case DebugLevel:
+		return BunyanDebugLevel
+	case InfoLevel:
+		return BunyanInfoLevel
+	case WarnLevel:
+		return BunyanWarnLevel
+	case ErrorLevel:
+		return BunyanErrorLevel
+	default:
+		return BunyanFatalLevel
+	}
+}
+
+// BunyanFormatter formats entries as the JSON records written by node-bunyan,
+// so the output can be read with the `bunyan` CLI and other bunyan tooling.
+type BunyanFormatter struct {
+	// Name of the application, written as `name`. Defaults to the name of the
+	// running executable.
+	Name string
+
+	hostname string
+	pid      int
+	once     sync.Once
+}
+
+func (f *BunyanFormatter) init() {
+	if f.Name == "" {
+		f.Name = filepath.Base(os.Args[0])
+	}
+	f.hostname, _ = os.Hostname()
+	f.pid = os.Getpid()
+}
+
+func (f *BunyanFormatter) Format(entry *Entry) ([]byte, error) {
+	f.once.Do(f.init)
+
+	data := make(Fields, len(entry.Data)+7)
+	for k, v := range entry.Data {
+		switch v := v.(type) {
+		case error:
+			// Otherwise errors are ignored by `encoding/json`
+			data[k] = v.Error()
+		default:
+			data[k] = v
+		}
+	}
+	prefixFieldClashes(data)
+	for _, k := range []string{"v", "name", "hostname", "pid"} {
+		if v, ok := data[k]; ok {
+			data["fields."+k] = v
+		}
+	}
+
+	data["v"] = 0
+	data["name"] = f.Name
+	data["hostname"] = f.hostname
+	data["pid"] = f.pid
+	data["level"] = BunyanLevel(entry.Level)
+	data["msg"] = entry.Message
+	data["time"] = entry.Time.UTC().Format(bunyanTimestampFormat)
+
+	serialized, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal fields to JSON, %v", err)
+	}
+	return append(serialized, '\n'), nil
+}
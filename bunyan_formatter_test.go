@@ -0,0 +1,68 @@
+package logrus
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBunyanLevel(t *testing.T) {
+	assert.Equal(t, 10, BunyanLevel(TraceLevel))
+	assert.Equal(t, 20, BunyanLevel(DebugLevel))
+	assert.Equal(t, 30, BunyanLevel(InfoLevel))
+	assert.Equal(t, 40, BunyanLevel(WarnLevel))
+	assert.Equal(t, 50, BunyanLevel(ErrorLevel))
+	assert.Equal(t, 60, BunyanLevel(FatalLevel))
+	assert.Equal(t, 60, BunyanLevel(PanicLevel))
+}
+
+func TestBunyanFormatter(t *testing.T) {
+	formatter := &BunyanFormatter{Name: "myapp"}
+
+	entry := WithFields(Fields{"foo": "bar", "err": errors.New("wild walrus"), "pid": "user pid"})
+	entry.Level = WarnLevel
+	entry.Message = "oh hai"
+	entry.Time = time.Date(2017, 8, 22, 10, 11, 12, 345000000, time.UTC)
+
+	b, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatal("Unable to format entry: ", err)
+	}
+
+	record := make(map[string]interface{})
+	if err := json.Unmarshal(b, &record); err != nil {
+		t.Fatal("Unable to unmarshal formatted entry: ", err)
+	}
+
+	hostname, _ := os.Hostname()
+	assert.Equal(t, 0.0, record["v"])
+	assert.Equal(t, "myapp", record["name"])
+	assert.Equal(t, hostname, record["hostname"])
+	assert.Equal(t, float64(os.Getpid()), record["pid"])
+	assert.Equal(t, 40.0, record["level"])
+	assert.Equal(t, "oh hai", record["msg"])
+	assert.Equal(t, "2017-08-22T10:11:12.345Z", record["time"])
+	assert.Equal(t, "bar", record["foo"])
+	assert.Equal(t, "wild walrus", record["err"])
+	assert.Equal(t, "user pid", record["fields.pid"])
+}
+
+func TestBunyanFormatterDefaultName(t *testing.T) {
+	formatter := &BunyanFormatter{}
+
+	b, err := formatter.Format(WithField("foo", "bar"))
+	if err != nil {
+		t.Fatal("Unable to format entry: ", err)
+	}
+
+	record := make(map[string]interface{})
+	if err := json.Unmarshal(b, &record); err != nil {
+		t.Fatal("Unable to unmarshal formatted entry: ", err)
+	}
+	assert.NotEmpty(t, record["name"])
+	assert.Equal(t, byte('\n'), b[len(b)-1])
+}
@@ -0,0 +1,102 @@
+package logrus
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Defines the keys used for the caller when ReportCaller is enabled.
+var FileKey = "file"
+var FuncKey = "func"
+
+// maximum number of frames inspected to find the caller
+const maxCallerDepth = 32
+
+var (
+	// the package path of logrus, frames within it are not reported as caller
+	logrusPackage     string
+	logrusPackageOnce sync.Once
+)
+
+func getLogrusPackage() string {
+	logrusPackageOnce.Do(func() {
+		name := runtime.FuncForPC(reflect.ValueOf(callerFrame).Pointer()).Name()
+		logrusPackage = name[:strings.LastIndex(name, ".")]
+	})
+	return logrusPackage
+}
+
+func isLogrusFrame(frame runtime.Frame) bool {
+	fn := frame.Function
+	pkg := getLogrusPackage()
+	if !strings.HasPrefix(fn, pkg) || len(fn) <= len(pkg) || fn[len(pkg)] != '.' {
+		return false
+	}
+	// tests living in the package are callers like any other
+	return !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// callerFrame returns the first frame outside of logrus, that is the code which
+// called the logging method, and then skips `skip` more frames up the stack.
+func callerFrame(skip int) (runtime.Frame, bool) {
+	pcs := make([]uintptr, maxCallerDepth)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	found := false
+	for {
+		frame, more := frames.Next()
+		if found || !isLogrusFrame(frame) {
+			found = true
+			if skip <= 0 {
+				return frame, true
+			}
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// logrusDepth returns the number of consecutive logrus frames on the stack,
+// starting with the function calling it. It is the number of frames to skip
+// for a stacktrace starting at the code which called into logrus.
+func logrusDepth() int {
+	pcs := make([]uintptr, maxCallerDepth)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		if !isLogrusFrame(frame) || !more {
+			return depth
+		}
+		depth++
+	}
+}
+
+// Add the file, line and function of a caller to the Entry, independently of
+// ReportCaller, e.g. for wrappers of the logger. With a skip of 0 the caller
+// is the function calling WithCaller, 1 is the function calling it, etc. The
+// caller set this way is kept when ReportCaller is enabled.
+func (entry *Entry) WithCaller(skip int) *Entry {
+	if entry.noop {
+		return entry
+	}
+	pcs := make([]uintptr, 1)
+	if runtime.Callers(skip+2, pcs) == 0 {
+		return entry
+	}
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	return entry.WithFields(callerFields(frame))
+}
+
+func callerFields(frame runtime.Frame) Fields {
+	return Fields{
+		FileKey: fmt.Sprintf("%s:%d", frame.File, frame.Line),
+		FuncKey: frame.Function,
+	}
+}
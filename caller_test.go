@@ -0,0 +1,101 @@
+package logrus
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nextLine returns the file:line of the line following its call site.
+func nextLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", file, line+1)
+}
+
+func TestReportCaller(t *testing.T) {
+	var line string
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetReportCaller(true)
+		line = nextLine()
+		log.Warning("test")
+	}, func(fields Fields) {
+		assert.Equal(t, line, fields[FileKey])
+		assert.Contains(t, fields[FuncKey], "TestReportCaller")
+	})
+}
+
+func TestReportCallerThroughEntry(t *testing.T) {
+	var line string
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetReportCaller(true)
+		line = nextLine()
+		log.WithField("foo", "bar").Printf("test %d", 1)
+	}, func(fields Fields) {
+		assert.Equal(t, line, fields[FileKey])
+	})
+}
+
+func TestReportCallerDisabledByDefault(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Info("test")
+	}, func(fields Fields) {
+		_, ok := fields[FileKey]
+		assert.False(t, ok)
+		_, ok = fields[FuncKey]
+		assert.False(t, ok)
+	})
+}
+
+func logThroughWrapper(log *Logger, msg string) {
+	log.Info(msg)
+}
+
+func TestReportCallerDepth(t *testing.T) {
+	var line string
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetReportCaller(true)
+		log.SetReportCallerDepth(1)
+		line = nextLine()
+		logThroughWrapper(log, "test")
+	}, func(fields Fields) {
+		assert.Equal(t, line, fields[FileKey])
+		assert.Contains(t, fields[FuncKey], "TestReportCallerDepth")
+	})
+}
+
+// logFailure is a logging helper, it attributes the entry to its own caller.
+func logFailure(log *Logger, msg string) {
+	log.WithField("failure", true).WithCaller(1).Error(msg)
+}
+
+func TestWithCaller(t *testing.T) {
+	var line string
+	LogAndAssertJSON(t, func(log *Logger) {
+		line = nextLine()
+		logFailure(log, "test")
+	}, func(fields Fields) {
+		assert.Equal(t, line, fields[FileKey])
+		assert.Contains(t, fields[FuncKey], "TestWithCaller")
+		assert.Equal(t, true, fields["failure"])
+	})
+}
+
+func TestWithCallerWinsOverReportCaller(t *testing.T) {
+	var line string
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetReportCaller(true)
+		line = nextLine()
+		logFailure(log, "test")
+	}, func(fields Fields) {
+		assert.Equal(t, line, fields[FileKey])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		line = nextLine()
+		NewEntry(log).WithCaller(0).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, line, fields[FileKey])
+	})
+}
@@ -0,0 +1,71 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// DefaultCEEPrefix is the prefix of the lines formatted by CEEFormatter when
+// none is set, as expected by the CEE-enhanced syslog collectors.
+const DefaultCEEPrefix = "@cee:"
+
+// DefaultCEESeverityKey is the key of the syslog severity in the lines
+// formatted by CEEFormatter when none is set.
+const DefaultCEESeverityKey = "severity"
+
+// CEEFormatter formats entries as JSON objects prefixed with `@cee:`, with the
+// syslog severity of the level, see SyslogSeverity, as their first field:
+//
+//    @cee:{"severity":6,"level":"info","msg":"started","time":"..."}
+//
+// The JSON body is the one of the embedded JSONFormatter, so its options
+// apply. A field of the entry named like the severity key gets the `fields.`
+// prefix.
+type CEEFormatter struct {
+	JSONFormatter
+
+	// Prefix of the lines, defaults to DefaultCEEPrefix.
+	Prefix string
+
+	// SeverityKey is the key of the syslog severity, defaults to
+	// DefaultCEESeverityKey.
+	SeverityKey string
+}
+
+func (f *CEEFormatter) Format(entry *Entry) ([]byte, error) {
+	prefix := f.Prefix
+	if prefix == "" {
+		prefix = DefaultCEEPrefix
+	}
+	key := f.SeverityKey
+	if key == "" {
+		key = DefaultCEESeverityKey
+	}
+
+	if v, ok := entry.Data[key]; ok {
+		clash := *entry
+		clash.Data = entry.fieldsWith(Fields{"fields." + key: v})
+		delete(clash.Data, key)
+		entry = &clash
+	}
+	serialized, err := f.JSONFormatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	// serialized is a JSON object, the severity is inserted as its first key
+	quotedKey, _ := json.Marshal(key)
+	b := bytes.NewBuffer(make([]byte, 0, len(prefix)+len(quotedKey)+len(serialized)+4))
+	b.WriteString(prefix)
+	b.WriteByte('{')
+	b.Write(quotedKey)
+	b.WriteByte(':')
+	b.WriteString(strconv.Itoa(SyslogSeverity(entry.Level)))
+	body := serialized[1:]
+	if len(body) > 0 && body[0] != '}' {
+		b.WriteByte(',')
+	}
+	b.Write(body)
+	return b.Bytes(), nil
+}
@@ -0,0 +1,37 @@
+package logrus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCEEFormatter(t *testing.T) {
+	formatter := &CEEFormatter{JSONFormatter: JSONFormatter{DisableTimestamp: true}}
+	entry := WithField("a", 1)
+	entry.Level = WarnLevel
+	entry.Message = "test"
+
+	b, err := formatter.Format(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, `@cee:{"severity":4,"a":1,"level":"warning","msg":"test"}`+"\n", string(b))
+}
+
+func TestCEEFormatterCustomPrefixAndKey(t *testing.T) {
+	formatter := &CEEFormatter{Prefix: "@cee: ", SeverityKey: "pri"}
+	entry := WithField("pri", "high")
+	entry.Level = ErrorLevel
+	entry.Message = "test"
+
+	b, err := formatter.Format(entry)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(b), `@cee: {"pri":3,`), string(b))
+
+	var fields Fields
+	assert.NoError(t, json.Unmarshal(b[len("@cee: "):], &fields))
+	assert.Equal(t, float64(3), fields["pri"])
+	assert.Equal(t, "high", fields["fields.pri"])
+	assert.Equal(t, "high", entry.Data["pri"], "the entry should not be modified")
+}
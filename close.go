@@ -0,0 +1,83 @@
+package logrus
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// closeErrors are the errors of the outputs, hooks and formatter closed by
+// Logger.Close.
+type closeErrors []error
+
+func (errs closeErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Close flushes the logger, see Flush, then closes its outputs, hooks and
+// formatter if they implement io.Closer, e.g. a BufferedWriter or a file. The
+// standard streams are never closed, and a closer shared by several of them,
+// e.g. a file set as Out and as a module's output, is closed once. The
+// returned error combines the errors of every step.
+//
+// Entries logged after Close don't fire the hooks and are written to stderr.
+// Closing a logger more than once does nothing.
+func (logger *Logger) Close() error {
+	if !atomic.CompareAndSwapInt32(&logger.closed, 0, 1) {
+		return nil
+	}
+
+	var errs closeErrors
+	if err := logger.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+	closed := make(map[interface{}]bool)
+	closeValue := func(v interface{}) {
+		if v == os.Stdout || v == os.Stderr {
+			return
+		}
+		if c, ok := v.(io.Closer); ok {
+			// e.g. a struct holding a slice can't be a map key
+			if reflect.TypeOf(c).Comparable() {
+				if closed[c] {
+					return
+				}
+				closed[c] = true
+			}
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	logger.mu.Lock()
+	closeValue(logger.Out)
+	for _, out := range logger.ModuleOutputs {
+		closeValue(out)
+	}
+	sinks := logger.Sinks
+	for _, sink := range sinks {
+		closeValue(sink.Out)
+	}
+	hooks := logger.uniqueHooks()
+	formatter := logger.Formatter
+	logger.mu.Unlock()
+	for _, hook := range hooks {
+		closeValue(hook)
+	}
+	closeValue(formatter)
+	for _, sink := range sinks {
+		closeValue(sink.Formatter)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+package logrus
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type closeHook struct {
+	fired  int
+	closed int
+	err    error
+}
+
+func (h *closeHook) Levels() []Level   { return AllLevels }
+func (h *closeHook) Fire(*Entry) error { h.fired++; return nil }
+func (h *closeHook) Close() error {
+	h.closed++
+	return h.err
+}
+
+func TestCloseFlushesPendingEntries(t *testing.T) {
+	var out bytes.Buffer
+	hook := &closeHook{}
+	logger := New()
+	logger.Out = NewBufferedWriter(&out, 0, time.Hour)
+	logger.Hooks.Add(hook)
+
+	logger.Info("pending")
+	assert.Equal(t, 0, out.Len(), "the entry should still be buffered")
+
+	assert.NoError(t, logger.Close())
+	assert.Contains(t, out.String(), "pending")
+	assert.Equal(t, 1, hook.closed)
+
+	assert.NotPanics(t, func() { logger.Info("after close") })
+	assert.NotContains(t, out.String(), "after close")
+	assert.Equal(t, 1, hook.fired, "hooks should not fire after Close")
+
+	assert.NoError(t, logger.Close())
+	assert.Equal(t, 1, hook.closed, "a second Close should do nothing")
+}
+
+func TestCloseCombinesErrors(t *testing.T) {
+	logger := New()
+	logger.Out = &flushWriter{err: errors.New("flush failed")}
+	logger.Hooks.Add(&closeHook{err: errors.New("close failed")})
+
+	err := logger.Close()
+	assert.EqualError(t, err, "flush failed; close failed")
+}
+
+type closeWriter struct {
+	bytes.Buffer
+	closed int
+}
+
+func (w *closeWriter) Close() error {
+	w.closed++
+	return nil
+}
+
+func TestCloseSharedOutputOnce(t *testing.T) {
+	out := &closeWriter{}
+	logger := New()
+	logger.Out = out
+	logger.SetModuleOutput("db", out)
+	logger.AddSink(out, nil, ErrorLevel)
+
+	assert.NoError(t, logger.Close())
+	assert.Equal(t, 1, out.closed)
+}
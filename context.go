@@ -0,0 +1,42 @@
+package logrus
+
+import "context"
+
+type entryContextKey struct{}
+
+type requestIDContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the entry, e.g. for middlewares
+// adding request scoped fields which are logged by the handlers:
+//
+//    ctx = logrus.NewContext(ctx, logrus.FromContext(ctx).WithField("user", user))
+//
+// Entries are not modified by WithField and friends, storing the enriched
+// entry again is what makes it visible to the next layers.
+func NewContext(ctx context.Context, entry *Entry) context.Context {
+	return context.WithValue(ctx, entryContextKey{}, entry)
+}
+
+// FromContext returns the entry stored in ctx by NewContext. When there is
+// none, it returns an entry of the standard logger with ctx set, so the
+// fields of the logger's ContextExtractor are still added.
+func FromContext(ctx context.Context) *Entry {
+	if entry, ok := ctx.Value(entryContextKey{}).(*Entry); ok && entry != nil {
+		return entry
+	}
+	return std.WithContext(ctx)
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the id of the request,
+// which is logged under RequestIDKey by the entries with that context, see
+// Entry.WithContext. It is meant to be called once by a middleware.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestIDFromContext returns the request id stored in ctx by
+// ContextWithRequestID, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
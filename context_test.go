@@ -0,0 +1,132 @@
+package logrus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTraceKey struct{}
+
+func testTraceExtractor(ctx context.Context) Fields {
+	traceID, ok := ctx.Value(testTraceKey{}).(string)
+	if !ok {
+		return nil
+	}
+	return Fields{"trace_id": traceID, "span_id": "span-" + traceID}
+}
+
+func TestContextExtractor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "abc")
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetContextExtractor(testTraceExtractor)
+		log.WithContext(ctx).WithField("foo", "bar").Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "abc", fields["trace_id"])
+		assert.Equal(t, "span-abc", fields["span_id"])
+		assert.Equal(t, "bar", fields["foo"])
+	})
+}
+
+func TestContextExtractorDoesNotOverrideFields(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "abc")
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetContextExtractor(testTraceExtractor)
+		log.WithField("trace_id", "explicit").WithContext(ctx).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "explicit", fields["trace_id"])
+		assert.Equal(t, "span-abc", fields["span_id"])
+	})
+}
+
+func TestContextWithoutExtractor(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "abc")
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		entry := log.WithContext(ctx)
+		assert.Equal(t, ctx, entry.WithField("foo", "bar").Context)
+		entry.Info("test")
+	}, func(fields Fields) {
+		_, ok := fields["trace_id"]
+		assert.False(t, ok)
+	})
+}
+
+func TestContextExtractorDoesNotModifyEntry(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "abc")
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetContextExtractor(testTraceExtractor)
+		entry := log.WithContext(ctx).WithField("foo", "bar")
+		entry.Info("test")
+		assert.Equal(t, Fields{"foo": "bar"}, entry.Data)
+	}, func(fields Fields) {
+		assert.Equal(t, "abc", fields["trace_id"])
+	})
+}
+
+func TestWithTraceContext(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		NewEntry(log).WithTraceContext("trace", "span", "parent").Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "trace", fields[TraceIDKey])
+		assert.Equal(t, "span", fields[SpanIDKey])
+		assert.Equal(t, "parent", fields[ParentSpanIDKey])
+	})
+}
+
+func TestWithTraceContextSkipsEmptyIDs(t *testing.T) {
+	data := NewEntry(New()).WithTraceContext("trace", "span", "").Data
+	assert.Equal(t, Fields{"trace_id": "trace", "span_id": "span"}, data)
+}
+
+func TestNewContextFromContext(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		ctx := NewContext(context.Background(), log.WithField("request_id", "42"))
+		// a middleware enriching the entry
+		ctx = NewContext(ctx, FromContext(ctx).WithField("user", "walrus"))
+		FromContext(ctx).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "42", fields["request_id"])
+		assert.Equal(t, "walrus", fields["user"])
+	})
+}
+
+func TestFromContextFallback(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "abc")
+	entry := FromContext(ctx)
+	assert.Equal(t, std, entry.Logger)
+	assert.Equal(t, ctx, entry.Context)
+	assert.Empty(t, entry.Data)
+}
+
+func TestWithRequestID(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		base := log.WithRequestID("req-1")
+		base.WithField("a", 1).WithModule("db").Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "req-1", fields[RequestIDKey])
+		assert.Equal(t, float64(1), fields["a"])
+	})
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	ctx := ContextWithRequestID(context.Background(), "req-2")
+	assert.Equal(t, "req-2", RequestIDFromContext(ctx))
+	assert.Equal(t, "", RequestIDFromContext(context.Background()))
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithContext(ctx).WithField("a", 1).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "req-2", fields[RequestIDKey])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithContext(ctx).WithRequestID("explicit").Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "explicit", fields[RequestIDKey], "an explicit request id should win")
+	})
+}
@@ -0,0 +1,132 @@
+package logrus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// LevelSpacing is the distance between the severities of the standard levels,
+// PanicLevel is 0, FatalLevel is LevelSpacing and so on, see Level.Severity.
+// The severities in between, and above TraceLevel's, are free for custom
+// levels.
+const LevelSpacing = 100
+
+type customLevel struct {
+	name     string
+	severity int
+}
+
+var (
+	customLevels     = map[Level]customLevel{}
+	customLevelNames = map[string]Level{}
+	customLevelsMu   sync.RWMutex
+	// the value of the last level registered, the values above TraceLevel are
+	// given in order to the custom levels
+	lastCustomLevel = TraceLevel
+)
+
+// RegisterLevel defines a custom level, e.g. an audit level logged with
+// Entry.Log, and returns it. Levels are compared by severity, the lower the
+// more severe, so a level of severity InfoLevel.Severity()-LevelSpacing/2 is
+// logged by a logger at InfoLevel but not at WarnLevel. PanicLevel's severity
+// being 0, no level is more severe than it. The value of the level itself is
+// only an identifier above TraceLevel. ParseLevel and Level.String know the
+// name, which is lowercased, and the level is added to AllLevels. The
+// formatters and hooks mapping levels to other schemes, e.g. SyslogSeverity,
+// treat it like the closest more severe standard level.
+//
+// Custom levels never exit or panic, even between PanicLevel and FatalLevel,
+// only Fatal and Panic do. Levels should be registered at init, before they
+// are used or any hook is added. RegisterLevel panics if the severity is not
+// positive or is a standard level's, or if the name or severity is already
+// registered for another level.
+func RegisterLevel(name string, severity int) Level {
+	name = strings.ToLower(name)
+	if severity <= 0 || severity%LevelSpacing == 0 && severity <= TraceLevel.Severity() {
+		panic(fmt.Sprintf("logrus: invalid severity %d for level %q", severity, name))
+	}
+	if level, err := ParseLevel(name); err == nil {
+		if _, ok := lookupCustomLevel(name); ok && level.Severity() == severity {
+			return level
+		}
+		panic(fmt.Sprintf("logrus: level %q is already registered", name))
+	}
+
+	customLevelsMu.Lock()
+	defer customLevelsMu.Unlock()
+	for _, existing := range customLevels {
+		if existing.severity == severity {
+			panic(fmt.Sprintf("logrus: severity %d is already registered for level %q", severity, existing.name))
+		}
+	}
+	lastCustomLevel++
+	level := lastCustomLevel
+	customLevels[level] = customLevel{name: name, severity: severity}
+	customLevelNames[name] = level
+
+	// AllLevels is ordered from the most to the least severe
+	i := 0
+	for i < len(AllLevels) && AllLevels[i].severity() < severity {
+		i++
+	}
+	AllLevels = append(AllLevels, 0)
+	copy(AllLevels[i+1:], AllLevels[i:])
+	AllLevels[i] = level
+	return level
+}
+
+// Severity returns the severity levels are compared by, the lower the more
+// severe: LevelSpacing times the value of a standard level, the severity it
+// was registered with for a custom level.
+func (level Level) Severity() int {
+	return level.severity()
+}
+
+func (level Level) severity() int {
+	if level <= TraceLevel {
+		return int(level) * LevelSpacing
+	}
+	customLevelsMu.RLock()
+	custom, ok := customLevels[level]
+	customLevelsMu.RUnlock()
+	if !ok {
+		return int(level) * LevelSpacing
+	}
+	return custom.severity
+}
+
+// enables reports whether a logger at the level logs the entries at lv, i.e.
+// whether lv is at least as severe.
+func (level Level) enables(lv Level) bool {
+	if level <= TraceLevel && lv <= TraceLevel {
+		return lv <= level
+	}
+	return lv.severity() <= level.severity()
+}
+
+func customLevelName(level Level) (string, bool) {
+	customLevelsMu.RLock()
+	defer customLevelsMu.RUnlock()
+	custom, ok := customLevels[level]
+	return custom.name, ok
+}
+
+func lookupCustomLevel(name string) (Level, bool) {
+	customLevelsMu.RLock()
+	defer customLevelsMu.RUnlock()
+	level, ok := customLevelNames[name]
+	return level, ok
+}
+
+// standardLevel returns the level itself for a standard level and the closest
+// more severe standard level for a custom level.
+func standardLevel(level Level) Level {
+	if level <= TraceLevel {
+		return level
+	}
+	if standard := Level(level.severity() / LevelSpacing); standard < TraceLevel {
+		return standard
+	}
+	return TraceLevel
+}
@@ -0,0 +1,91 @@
+package logrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// registerTestLevel registers a custom level and returns a function removing
+// it, so the levels don't leak into the other tests.
+func registerTestLevel(name string, severity int) (Level, func()) {
+	level := RegisterLevel(name, severity)
+	return level, func() {
+		customLevelsMu.Lock()
+		defer customLevelsMu.Unlock()
+		delete(customLevels, level)
+		delete(customLevelNames, name)
+		for i, l := range AllLevels {
+			if l == level {
+				AllLevels = append(AllLevels[:i], AllLevels[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func TestRegisterLevel(t *testing.T) {
+	audit, unregister := registerTestLevel("audit", InfoLevel.Severity()-LevelSpacing/2)
+	defer unregister()
+
+	assert.True(t, audit > TraceLevel, "the standard levels should keep their values")
+	assert.True(t, InfoLevel.enables(audit) && !WarnLevel.enables(audit))
+	assert.Equal(t, "audit", audit.String())
+	parsed, err := ParseLevel("AUDIT")
+	assert.NoError(t, err)
+	assert.Equal(t, audit, parsed)
+	assert.Equal(t, []Level{PanicLevel, FatalLevel, ErrorLevel, WarnLevel, audit, InfoLevel, DebugLevel, TraceLevel}, AllLevels)
+	assert.Equal(t, audit, RegisterLevel("audit", audit.Severity()), "registering the same level again should be allowed")
+
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+
+	logger.Log(audit, "logged")
+	assert.Equal(t, "level=audit msg=logged \n", buffer.String())
+
+	buffer.Reset()
+	logger.Level = WarnLevel
+	logger.Log(audit, "dropped")
+	assert.Equal(t, "", buffer.String())
+
+	logger.Level = audit
+	logger.Info("dropped")
+	logger.Warn("logged")
+	assert.Equal(t, "level=warning msg=logged \n", buffer.String(), "a logger at a custom level should compare the levels by severity")
+}
+
+func TestRegisterLevelInvalid(t *testing.T) {
+	assert.Panics(t, func() { RegisterLevel("zero", 0) })
+	assert.Panics(t, func() { RegisterLevel("info2", InfoLevel.Severity()) })
+	assert.Panics(t, func() { RegisterLevel("warn", WarnLevel.Severity()+1) })
+
+	_, unregister := registerTestLevel("notice", WarnLevel.Severity()+10)
+	defer unregister()
+	assert.Panics(t, func() { RegisterLevel("notice", WarnLevel.Severity()+20) })
+	assert.Panics(t, func() { RegisterLevel("other", WarnLevel.Severity()+10) })
+}
+
+func TestCustomLevelDoesNotPanic(t *testing.T) {
+	security, unregister := registerTestLevel("security", LevelSpacing/2)
+	defer unregister()
+
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	assert.NotPanics(t, func() { logger.Log(security, "breach") })
+	assert.Contains(t, buffer.String(), "level=security")
+	assert.Equal(t, SyslogCritical, SyslogSeverity(security), "the level should map like PanicLevel")
+}
+
+func TestCustomLevelColoredText(t *testing.T) {
+	dbg, unregister := registerTestLevel("dbg", DebugLevel.Severity()+LevelSpacing/2)
+	defer unregister()
+
+	formatter := &TextFormatter{ForceColors: true, DisableTimestamp: true}
+	b, err := formatter.Format(&Entry{Level: dbg, Message: "test", Data: Fields{}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "DBG ")
+}
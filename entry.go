@@ -2,9 +2,13 @@ package logrus
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/yyscamper/errors"
@@ -17,19 +21,50 @@ const (
 
 var bufferPool *sync.Pool
 
+// loggedEntryPool holds the copies made by log, see Logger.PoolEntries.
+var loggedEntryPool *sync.Pool
+
 func init() {
 	bufferPool = &sync.Pool{
 		New: func() interface{} {
 			return new(bytes.Buffer)
 		},
 	}
+	loggedEntryPool = &sync.Pool{
+		New: func() interface{} {
+			return &Entry{Data: make(Fields, 6)}
+		},
+	}
 }
 
 // Defines the key when adding errors using WithError.
 var ErrorKey = "error"
+
+// Defines the key when adding errors using WithErrors.
+var ErrorsKey = "errors"
 var StacktraceKey = "stacktrace"
 var ModuleNameKey = "module"
 
+// Defines the key used to report malformed With calls when StrictFields is set.
+var FieldErrorKey = "__log_error"
+
+// Defines the keys used for distributed tracing ids by WithTraceContext.
+// Context extractors should use the same keys, see Logger.SetContextExtractor.
+var TraceIDKey = "trace_id"
+var SpanIDKey = "span_id"
+var ParentSpanIDKey = "parent_span_id"
+
+// Defines the key of the request id added by WithRequestID, or taken from the
+// context of the entry, see ContextWithRequestID.
+var RequestIDKey = "request_id"
+
+// Defines the key used for the goroutine id when ReportGoroutineID is enabled.
+var GoroutineIDKey = "goroutine"
+
+// Defines the key a hook can set to true to cancel the output of an entry,
+// see Entry.Drop.
+var DropKey = "__drop"
+
 // An entry is the final or intermediate Logrus logging entry. It contains all
 // the fields passed with WithField{,s}. It's finally logged when Debug, Info,
 // Warn, Error, Fatal or Panic is called on it. These objects can be reused and
@@ -37,7 +72,11 @@ var ModuleNameKey = "module"
 type Entry struct {
 	Logger *Logger
 
-	// Contains all the fields set by the user.
+	// Contains all the fields set by the user. Entries derived with the With
+	// functions share nothing, but the map of an entry must not be modified
+	// once the entry is shared, e.g. by several goroutines. The hooks and the
+	// formatters get a copy they may modify. The entry passed to the hooks is
+	// not modified once they all fired, so they may keep it.
 	Data Fields
 
 	// Time at which the log entry was created
@@ -51,8 +90,37 @@ type Entry struct {
 
 	// When formatter is called in entry.log(), an Buffer may be set to entry
 	Buffer *bytes.Buffer
+
+	// Contains the context set by the user, see WithContext
+	Context context.Context
+
+	// Set on the entry returned by If(false), which discards everything
+	noop bool
+
+	// Fields only added when logged verbosely enough, see WithFieldAtLevel
+	levelFields []levelField
+
+	// Set on the entries Extend returned, whose Data was allocated for them
+	// alone
+	owned bool
+
+	// Stacktrace of the error added by WithError, only logged at the
+	// logger's StacktraceMinLevel or a more severe level
+	errorStack string
+
+	// Keys of the fields added with WithOrderedFields, in insertion order
+	keyOrder []string
+
+	// Loggers this entry was forwarded from by a TeeHook
+	teedFrom []*Logger
+
+	// Set by hooks calling Drop, the entry is not written
+	dropped bool
 }
 
+// noopEntry is shared by all the If(false) calls so they don't allocate.
+var noopEntry = &Entry{noop: true}
+
 func NewEntry(logger *Logger) *Entry {
 	return &Entry{
 		Logger: logger,
@@ -64,7 +132,7 @@ func NewEntry(logger *Logger) *Entry {
 // Returns the string representation from the reader and ultimately the
 // formatter.
 func (entry *Entry) String() (string, error) {
-	serialized, err := entry.Logger.Formatter.Format(entry)
+	serialized, err := entry.Bytes()
 	if err != nil {
 		return "", err
 	}
@@ -72,37 +140,183 @@ func (entry *Entry) String() (string, error) {
 	return str, nil
 }
 
+// Returns the entry formatted by the formatter of the logger, e.g. for hooks
+// forwarding entries to byte oriented transports. The entry is formatted
+// without the pooled buffer used by log(), so the result is safe to retain.
+func (entry *Entry) Bytes() ([]byte, error) {
+	if entry.Buffer == nil {
+		return entry.Logger.Formatter.Format(entry)
+	}
+	unbuffered := *entry
+	unbuffered.Buffer = nil
+	return entry.Logger.Formatter.Format(&unbuffered)
+}
+
+// SortedKeys returns the keys of the entry's data in a stable order for
+// formatters: the keys of the logger's FieldOrder first, then the ones added
+// with WithOrderedFields, then the others alphabetically.
+func (entry *Entry) SortedKeys() []string {
+	return sortedKeys(entry.Data, entry.fieldOrder())
+}
+
+// fieldOrder returns the keys placed first by the formatters, the logger's
+// FieldOrder then the keys added with WithOrderedFields.
+func (entry *Entry) fieldOrder() []string {
+	var order []string
+	if entry.Logger != nil {
+		order = entry.Logger.FieldOrder
+	}
+	if len(entry.keyOrder) == 0 {
+		return order
+	}
+	if len(order) == 0 {
+		return entry.keyOrder
+	}
+	return append(order[:len(order):len(order)], entry.keyOrder...)
+}
+
+// MarshalJSON returns the canonical JSON representation of the entry, an
+// object with the time, level, module and msg keys and the remaining fields,
+// independently of the formatter of the logger. Errors are serialized to
+// their message and fields clashing with the keys get the `fields.` prefix.
+func (entry *Entry) MarshalJSON() ([]byte, error) {
+	data := make(Fields, len(entry.Data)+4)
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		data[k] = v
+	}
+	prefixFieldClashes(data)
+	data[FieldKeyTime] = entry.Time.Format(time.RFC3339Nano)
+	data[FieldKeyLevel] = entry.Level.String()
+	data[FieldKeyMsg] = entry.Message
+	if _, ok := data[ModuleNameKey]; !ok {
+		data[ModuleNameKey] = DefaultModuleName
+	}
+	return json.Marshal(data)
+}
+
 func (entry *Entry) withStack(trace string) *Entry {
 	return entry.WithField(StacktraceKey, trace)
 }
 
 // Add current stacktrace to the Entry
 func (entry *Entry) WithStack() *Entry {
+	if entry.noop {
+		return entry
+	}
 	return entry.withStack(errors.Stack(1))
 }
 
+// mergeStack returns the stacktrace to store when trace is added to an entry
+// which may already carry one, e.g. `WithStack().WithError(err)`. Stacks
+// captured along the same call path share most of their outer frames, in this
+// case only the more complete one is kept instead of two nearly identical
+// stacks. An unrelated stack simply replaces the previous one.
+func (entry *Entry) mergeStack(trace string) string {
+	prev, ok := entry.Data[StacktraceKey].(string)
+	if !ok || prev == trace {
+		return trace
+	}
+	prevLines := strings.Split(strings.TrimSpace(prev), "\n")
+	lines := strings.Split(strings.TrimSpace(trace), "\n")
+	shorter := len(lines)
+	if len(prevLines) < shorter {
+		shorter = len(prevLines)
+	}
+	common := 0
+	for common < shorter && prevLines[len(prevLines)-1-common] == lines[len(lines)-1-common] {
+		common++
+	}
+	if common*2 < shorter {
+		return trace
+	}
+	if len(prevLines) > len(lines) {
+		return prev
+	}
+	return trace
+}
+
+// Returns the Entry when cond is true and otherwise an entry on which the
+// logging functions do nothing, without formatting their arguments. Replaces
+// `if cond { entry.Info(...) }`. Fields added to the discarding entry are
+// discarded as well.
+func (entry *Entry) If(cond bool) *Entry {
+	if cond {
+		return entry
+	}
+	return noopEntry
+}
+
+// Returns an entry on which the logging functions do nothing, for an entry
+// built conditionally and finally not worth logging. It is If(false).
+func (entry *Entry) Discard() *Entry {
+	return noopEntry
+}
+
+// Drop cancels the output of the entry being logged, it is meant to be called
+// by hooks filtering entries, e.g. health check requests. The remaining hooks
+// are still fired, and Fatal and Panic still exit and panic. Setting the
+// DropKey field to true in a hook has the same effect, but as the data of the
+// entry may be shared with other entries, Drop should be preferred.
+func (entry *Entry) Drop() {
+	entry.dropped = true
+}
+
 // Add an error as single field (using the key defined in ErrorKey) to the Entry.
+// The stacktrace of the error, or of the call for other errors unless the
+// logger's DisableStackOnError is set, is added under StacktraceKey when the
+// entry is logged at the logger's StacktraceMinLevel or a more severe level,
+// see SetStacktraceMinLevel.
 func (entry *Entry) WithError(err error) *Entry {
+	if entry.noop {
+		return entry
+	}
 	switch realErr := err.(type) {
 	case *errors.Error:
-		fields := Fields{
-			ErrorKey:      err,
-			StacktraceKey: realErr.Stack(),
-		}
+		fields := Fields{ErrorKey: err}
 		if realErr.Name != "" {
 			fields[ModuleNameKey] = realErr.Name
 		}
 		for k, v := range realErr.Fields {
 			fields[k] = v
 		}
-		return entry.WithFields(fields)
+		derived := entry.WithFields(fields)
+		derived.errorStack = realErr.Stack()
+		return derived
 	default:
-		fields := Fields{
-			ErrorKey:      err,
-			StacktraceKey: errors.Stack(2),
+		derived := entry.WithFields(Fields{ErrorKey: err})
+		if !entry.Logger.DisableStackOnError {
+			derived.errorStack = errors.Stack(2)
+		}
+		return derived
+	}
+}
+
+// Add several errors as a single field (using the key defined in ErrorsKey) to
+// the Entry, e.g. the errors collected by an operation on several items. Each
+// error is added as an object with its message under ErrorKey, and for an
+// *errors.Error its own stacktrace and module name. Nil errors are skipped.
+func (entry *Entry) WithErrors(errs ...error) *Entry {
+	if entry.noop {
+		return entry
+	}
+	list := make([]Fields, 0, len(errs))
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		item := Fields{ErrorKey: err.Error()}
+		if realErr, ok := err.(*errors.Error); ok {
+			item[StacktraceKey] = realErr.Stack()
+			if realErr.Name != "" {
+				item[ModuleNameKey] = realErr.Name
+			}
 		}
-		return entry.WithFields(fields)
+		list = append(list, item)
 	}
+	return entry.WithField(ErrorsKey, list)
 }
 
 // Add a single field to the Entry.
@@ -110,8 +324,118 @@ func (entry *Entry) WithField(key string, value interface{}) *Entry {
 	return entry.WithFields(Fields{key: value})
 }
 
+// Add a single field to the Entry unless it already has the key, in which
+// case the entry itself is returned, e.g. for an enrichment which may run
+// twice in a middleware chain. Pass a Valuer to only compute an expensive
+// value when the entry is logged.
+func (entry *Entry) WithFieldOnce(key string, value interface{}) *Entry {
+	if _, ok := entry.Data[key]; ok || entry.noop {
+		return entry
+	}
+	return entry.withOne(key, value)
+}
+
+// Add a field to the Entry with the value formatted according to a format
+// specifier, `WithFieldf("url", "%s/%d", host, id)`.
+func (entry *Entry) WithFieldf(key string, format string, args ...interface{}) *Entry {
+	if entry.noop {
+		return entry
+	}
+	return entry.withOne(key, fmt.Sprintf(format, args...))
+}
+
+// withOne adds a single field without building a Fields map for it.
+func (entry *Entry) withOne(key string, value interface{}) *Entry {
+	if entry.noop {
+		return entry
+	}
+	data := make(Fields, len(entry.Data)+1)
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	data[key] = value
+	return entry.withData(data)
+}
+
 // Add a map of fields to the Entry.
 func (entry *Entry) WithFields(fields Fields) *Entry {
+	if entry.noop {
+		return entry
+	}
+	return entry.withData(entry.fieldsWith(fields))
+}
+
+// Add a map of fields to the Entry, modifying it in place when it is the
+// result of Extend, and otherwise like WithFields. This spares a map
+// allocation per call in chains like
+// `logger.WithField("a", 1).Extend(f1).Extend(f2)`.
+//
+// Only call Extend again on the result of Extend while you own it: while it
+// was not passed around, isn't used as the base of other entries and was not
+// logged yet, since they would all see the new fields.
+func (entry *Entry) Extend(fields Fields) *Entry {
+	if !entry.owned {
+		derived := entry.WithFields(fields)
+		derived.owned = true
+		return derived
+	}
+	for k, v := range fields {
+		entry.Data[k] = v
+	}
+	return entry
+}
+
+// Add a map of fields to the Entry, skipping the keys the Entry already has.
+// Unlike WithFields the existing values win, which is useful to layer base
+// context under fields that were set more specifically.
+func (entry *Entry) WithFieldsIfAbsent(fields Fields) *Entry {
+	if entry.noop {
+		return entry
+	}
+	return entry.withData(entry.fieldsIfAbsent(fields))
+}
+
+// Add a context to the Entry. When the logger has a ContextExtractor it is
+// used to add fields, e.g. trace and span ids, taken from the context.
+func (entry *Entry) WithContext(ctx context.Context) *Entry {
+	if entry.noop {
+		return entry
+	}
+	derived := entry.withData(entry.Data)
+	derived.Context = ctx
+	return derived
+}
+
+// Set the time of the Entry, used instead of the current time when it is
+// logged, e.g. to log events which happened earlier or to get a deterministic
+// output in tests.
+func (entry *Entry) WithTime(t time.Time) *Entry {
+	if entry.noop {
+		return entry
+	}
+	derived := entry.withData(entry.Data)
+	derived.Time = t
+	return derived
+}
+
+// withData returns a new entry with the given data and the other settings of
+// the entry, like its context and time.
+func (entry *Entry) withData(data Fields) *Entry {
+	return &Entry{
+		Logger:      entry.Logger,
+		Data:        data,
+		Time:        entry.Time,
+		Context:     entry.Context,
+		levelFields: entry.levelFields,
+		errorStack:  entry.errorStack,
+		keyOrder:    entry.keyOrder,
+	}
+}
+
+// fieldsWith returns a copy of the entry's data with the given fields added.
+// It is used by log() which must not modify the map shared with the entry it
+// was called on.
+func (entry *Entry) fieldsWith(fields Fields) Fields {
 	data := make(Fields, len(entry.Data)+len(fields))
 	for k, v := range entry.Data {
 		data[k] = v
@@ -119,7 +443,30 @@ func (entry *Entry) WithFields(fields Fields) *Entry {
 	for k, v := range fields {
 		data[k] = v
 	}
-	return &Entry{Logger: entry.Logger, Data: data}
+	return data
+}
+
+// fieldsIfAbsent is like fieldsWith but fields already set on the entry are
+// kept.
+func (entry *Entry) fieldsIfAbsent(fields Fields) Fields {
+	data := make(Fields, len(entry.Data)+len(fields))
+	for k, v := range fields {
+		data[k] = v
+	}
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	return data
+}
+
+// contextFields returns the entry's data merged with the fields extracted from
+// its context. Fields set explicitly on the entry are never overridden.
+func (entry *Entry) contextFields() Fields {
+	extracted := entry.Logger.ContextExtractor(entry.Context)
+	if len(extracted) == 0 {
+		return entry.Data
+	}
+	return entry.fieldsIfAbsent(extracted)
 }
 
 func stringify(val interface{}) string {
@@ -130,77 +477,261 @@ func stringify(val interface{}) string {
 }
 
 // Add one or multiple field to the Entry.
+//
+// The extras are key/value pairs. By default a non-string key is converted
+// to a string and a key missing its value gets nil. When the logger has
+// StrictFields set such calls are reported instead in the field defined by
+// FieldErrorKey, and a key missing its value is not added.
 func (entry *Entry) With(key string, value interface{}, extras ...interface{}) *Entry {
 	fields := Fields{}
 	fields[key] = value
 
+	strict := entry.Logger != nil && entry.Logger.StrictFields
+	var problems []string
+
 	n := len(extras)
 	if n%2 != 0 {
 		n--
 	}
 	for i := 0; i < n; i += 2 {
+		if _, ok := extras[i].(string); !ok && strict {
+			problems = append(problems, fmt.Sprintf("non-string key %#v at argument %d", extras[i], i+2))
+		}
 		fields[stringify(extras[i])] = extras[i+1]
 	}
-	//Auto attaches a value if user forgets the last value
 	if n < len(extras) {
-		fields[stringify(extras[n])] = nil
+		if strict {
+			problems = append(problems, fmt.Sprintf("odd number of arguments, missing value for key %q", stringify(extras[n])))
+		} else {
+			//Auto attaches a value if user forgets the last value
+			fields[stringify(extras[n])] = nil
+		}
+	}
+	if len(problems) > 0 {
+		fields[FieldErrorKey] = strings.Join(problems, "; ")
+	}
+	return entry.WithFields(fields)
+}
+
+// Add the ids of a distributed trace to the Entry, for systems passing them
+// along in headers rather than in a context. Empty ids are not added, e.g. the
+// parent span id of a root span.
+func (entry *Entry) WithTraceContext(traceID, spanID, parentSpanID string) *Entry {
+	fields := Fields{}
+	for key, id := range map[string]string{
+		TraceIDKey:      traceID,
+		SpanIDKey:       spanID,
+		ParentSpanIDKey: parentSpanID,
+	} {
+		if id != "" {
+			fields[key] = id
+		}
 	}
 	return entry.WithFields(fields)
 }
 
+// Add the id of the request being served to the Entry, under RequestIDKey.
+// Middlewares should rather store it once in the request's context with
+// ContextWithRequestID, it is then logged by the entries with that context.
+func (entry *Entry) WithRequestID(id string) *Entry {
+	return entry.withOne(RequestIDKey, id)
+}
+
 func (entry *Entry) WithModule(moduleName string) *Entry {
 	return entry.WithField(ModuleNameKey, moduleName)
 }
 
-// This function is not declared with a pointer value because otherwise
-// race conditions will occur when using multiple goroutines
-func (entry Entry) log(level Level, msg string) {
-	var buffer *bytes.Buffer
-	entry.Time = time.Now()
+// log logs a copy of the entry, which may be shared by several goroutines.
+// The copy comes from a pool when the logger has PoolEntries set, and goes
+// back to it unless the hooks or the buffering may keep it.
+func (entry *Entry) log(level Level, msg string) {
+	if !entry.Logger.PoolEntries {
+		logged := *entry
+		logged.write(level, msg, nil)
+		return
+	}
+	logged := loggedEntryPool.Get().(*Entry)
+	spare := logged.Data
+	*logged = *entry
+	if logged.write(level, msg, spare) {
+		for k := range spare {
+			delete(spare, k)
+		}
+		*logged = Entry{Data: spare}
+		loggedEntryPool.Put(logged)
+	}
+}
+
+// write resolves the fields of the entry, the copy made by log, and emits it.
+// When spare is a map the snapshot of the fields is made in it. It returns
+// whether the entry can be reused.
+func (entry *Entry) write(level Level, msg string, spare Fields) bool {
+	if entry.Time.IsZero() {
+		entry.Time = entry.Logger.now()
+	}
 	entry.Level = level
 	entry.Message = msg
 
-	if err := entry.Logger.Hooks.Fire(level, &entry); err != nil {
-		entry.Logger.mu.Lock()
-		fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
-		entry.Logger.mu.Unlock()
+	if entry.Context != nil && entry.Logger.ContextExtractor != nil {
+		entry.Data = entry.contextFields()
+	}
+	if entry.Context != nil {
+		if id := RequestIDFromContext(entry.Context); id != "" {
+			entry.Data = entry.fieldsIfAbsent(Fields{RequestIDKey: id})
+		}
+	}
+	if len(entry.levelFields) > 0 {
+		entry.Data = entry.fieldsAtLevel(level)
+	}
+	if entry.errorStack != "" && entry.Logger.stacktraceMinLevel().enables(level) {
+		entry.Data = entry.fieldsWith(Fields{StacktraceKey: entry.mergeStack(entry.errorStack)})
+	}
+	if defaults := entry.Logger.loadLevelDefaults(); len(defaults.fields) > 0 || len(defaults.stacks) > 0 {
+		entry.Data = entry.levelDefaultFields(defaults, level)
+	}
+	if entry.Logger.ReportCaller {
+		if frame, ok := callerFrame(entry.Logger.ReportCallerDepth); ok {
+			entry.Data = entry.fieldsIfAbsent(callerFields(frame))
+		}
+	}
+	if entry.Logger.ProcessFields {
+		entry.Data = entry.processFields()
+	}
+	if len(entry.Logger.EnvFields) > 0 {
+		entry.Data = entry.fieldsIfAbsent(entry.Logger.EnvFields)
+	}
+	if entry.Logger.ReportGoroutineID {
+		entry.Data = entry.fieldsWith(Fields{GoroutineIDKey: goroutineID()})
+	}
+	entry.Data = entry.resolvedValuers()
+
+	if secretPatterns := entry.Logger.loadSecretPatterns(); len(secretPatterns) > 0 {
+		entry.Message = maskSecrets(secretPatterns, entry.Message)
+		entry.Data = entry.maskedFields(secretPatterns)
+	}
+	if entry.Logger.MaxMessageBytes > 0 {
+		entry.Message = truncateMessage(entry.Message, entry.Logger.MaxMessageBytes)
+	}
+	if entry.Logger.MaxFields > 0 {
+		entry.Data = entry.truncatedFields()
+	}
+
+	// the hooks and formatters may modify the data, which until now can be the
+	// map of the entry log was called on, shared with other goroutines
+	if spare != nil {
+		for k, v := range entry.Data {
+			spare[k] = v
+		}
+		entry.Data = spare
+	} else {
+		entry.Data = entry.fieldsWith(nil)
+	}
+
+	buffered := entry.Logger.buffer(entry)
+	fired := false
+	if !buffered {
+		fired = entry.emit()
+	}
+
+	// To avoid Entry#log() returning a value that only would make sense for
+	// panic() to use in Entry#Panic(), we avoid the allocation by checking
+	// directly here.
+	if level == PanicLevel {
+		panic(entry)
+	}
+	return !buffered && !fired
+}
+
+// emit fires the hooks and writes the entry, once log() has resolved all its
+// fields. It reports whether hooks were fired, they may have kept the entry.
+func (entry *Entry) emit() bool {
+	if entry.Logger.nestedEmit() {
+		entry.emitNested()
+		return false
+	}
+	return entry.emitUnguarded()
+}
+
+// emitUnguarded is emit without the reentrancy check, for the entries a
+// TeeHook forwards from inside the emit of another logger. It reports whether
+// hooks were fired.
+func (entry *Entry) emitUnguarded() bool {
+	logger := entry.Logger
+	// the hooks and sinks may be replaced or added to while logging, they are
+	// only read once, under the lock ReplaceHooks, AddSink and SetEventSink take
+	logger.mu.Lock()
+	hooks := logger.Hooks[entry.Level]
+	sinks := logger.Sinks
+	eventSink := logger.EventSink
+	logger.mu.Unlock()
+
+	closed := atomic.LoadInt32(&entry.Logger.closed) == 1
+	fired := !closed && len(hooks) > 0
+	if fired {
+		if err := callHooks(hooks, entry); err != nil {
+			entry.Logger.handleError("Failed to fire hook: %v\n", err)
+		}
+		// the hooks may keep the entry, e.g. to ship it later, the formatters
+		// and the writes below work on a copy so it doesn't change afterwards
+		kept := entry
+		entry = &Entry{}
+		*entry = *kept
+		entry.Data = kept.fieldsWith(nil)
+	}
+	if entry.dropped || entry.Data[DropKey] == true {
+		return fired
+	}
+	if eventSink != nil {
+		entry.sendEvent(eventSink)
+		if len(sinks) > 0 && !closed {
+			entry.writeSinks(sinks, nil, false)
+		}
+		return fired
 	}
-	buffer = bufferPool.Get().(*bytes.Buffer)
+	if entry.Logger.EmitFormatMeta {
+		entry.Logger.formatMetaOnce.Do(entry.Logger.writeFormatMeta)
+	}
+	buffer := bufferPool.Get().(*bytes.Buffer)
 	buffer.Reset()
 	defer bufferPool.Put(buffer)
 	entry.Buffer = buffer
-	serialized, err := entry.Logger.Formatter.Format(&entry)
+	serialized, err := entry.Logger.Formatter.Format(entry)
+	if err != nil && entry.Logger.FallbackFormatter != nil {
+		buffer.Reset()
+		serialized, err = entry.Logger.FallbackFormatter.Format(entry)
+	}
+	formatted := err == nil
 	entry.Buffer = nil
 	if err != nil {
-		entry.Logger.mu.Lock()
-		fmt.Fprintf(os.Stderr, "Failed to obtain reader, %v\n", err)
-		entry.Logger.mu.Unlock()
+		entry.Logger.handleError("Failed to obtain reader, %v\n", err)
 	} else {
 		entry.Logger.mu.Lock()
-		_, err = entry.Logger.Out.Write(serialized)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+		out := entry.Logger.moduleOutput(entry.ModuleName())
+		if out == nil || closed {
+			// Not configured or closed, don't crash in the middle of logging
+			out = os.Stderr
 		}
+		_, err = entry.Logger.writeOut(out, serialized)
 		entry.Logger.mu.Unlock()
+		if err != nil {
+			entry.Logger.handleError("Failed to write to log, %v\n", err)
+		}
 	}
-
-	// To avoid Entry#log() returning a value that only would make sense for
-	// panic() to use in Entry#Panic(), we avoid the allocation by checking
-	// directly here.
-	if level <= PanicLevel {
-		panic(&entry)
+	if len(sinks) > 0 && !closed {
+		entry.writeSinks(sinks, serialized, formatted)
 	}
+	return fired
 }
 
 func (entry *Entry) Trace(args ...interface{}) {
 	if entry.matchLevel(TraceLevel) {
-		entry.log(TraceLevel, spew.Sdump(args...))
+		entry.log(TraceLevel, entry.sdump(args...))
 	}
 }
 
 func (entry *Entry) Debug(args ...interface{}) {
 	if entry.matchLevel(DebugLevel) {
-		entry.log(DebugLevel, spew.Sdump(args...))
+		entry.log(DebugLevel, entry.sdump(args...))
 	}
 }
 
@@ -210,13 +741,13 @@ func (entry *Entry) Print(args ...interface{}) {
 
 func (entry *Entry) Info(args ...interface{}) {
 	if entry.matchLevel(InfoLevel) {
-		entry.log(InfoLevel, spew.Sdump(args...))
+		entry.log(InfoLevel, entry.sdump(args...))
 	}
 }
 
 func (entry *Entry) Warn(args ...interface{}) {
 	if entry.matchLevel(WarnLevel) {
-		entry.log(WarnLevel, spew.Sdump(args...))
+		entry.log(WarnLevel, entry.sdump(args...))
 	}
 }
 
@@ -226,29 +757,85 @@ func (entry *Entry) Warning(args ...interface{}) {
 
 func (entry *Entry) Error(args ...interface{}) {
 	if entry.matchLevel(ErrorLevel) {
-		entry.log(ErrorLevel, spew.Sdump(args...))
+		entry.log(ErrorLevel, entry.sdump(args...))
 	}
 }
 
 func (entry *Entry) Fatal(args ...interface{}) {
+	if entry.noop {
+		return
+	}
+	if entry.matchLevel(FatalLevel) {
+		entry.log(FatalLevel, entry.sdump(args...))
+	}
+	Exit(entry.Logger.fatalExitCode())
+}
+
+// FatalWithCode is like Fatal but exits with the given code instead of the
+// logger's FatalExitCode, e.g. a code with a meaning to the orchestrator.
+func (entry *Entry) FatalWithCode(code int, args ...interface{}) {
+	if entry.noop {
+		return
+	}
 	if entry.matchLevel(FatalLevel) {
-		entry.log(FatalLevel, spew.Sdump(args...))
+		entry.log(FatalLevel, entry.sdump(args...))
 	}
-	Exit(1)
+	Exit(code)
 }
 
 func (entry *Entry) Panic(args ...interface{}) {
+	if entry.noop {
+		return
+	}
 	if entry.matchLevel(PanicLevel) {
-		entry.log(PanicLevel, spew.Sdump(args...))
+		entry.log(PanicLevel, entry.sdump(args...))
+	}
+	panic(entry.sdump(args...))
+}
+
+// Log logs at a level chosen at runtime, e.g. from the severity of an error.
+// A FatalLevel or PanicLevel entry exits or panics like Fatal and Panic.
+func (entry *Entry) Log(level Level, args ...interface{}) {
+	switch level {
+	case FatalLevel:
+		entry.Fatal(args...)
+	case PanicLevel:
+		entry.Panic(args...)
+	default:
+		if entry.matchLevel(level) {
+			entry.log(level, entry.sdump(args...))
+		}
+	}
+}
+
+// LogIf logs at a level chosen at runtime when cond is true, and otherwise
+// does nothing without formatting the arguments, like If(cond).Log. A false
+// cond never exits or panics, whatever the level.
+func (entry *Entry) LogIf(cond bool, level Level, args ...interface{}) {
+	if cond {
+		entry.Log(level, args...)
 	}
-	panic(spew.Sdump(args...))
 }
 
 // Entry Printf family functions
 
+// Logf is the Printf flavor of Log.
+func (entry *Entry) Logf(level Level, format string, args ...interface{}) {
+	switch level {
+	case FatalLevel:
+		entry.Fatalf(format, args...)
+	case PanicLevel:
+		entry.Panicf(format, args...)
+	default:
+		if entry.matchLevel(level) {
+			entry.log(level, entry.spewConfig().Sprintf(format, args...))
+		}
+	}
+}
+
 func (entry *Entry) Tracef(format string, args ...interface{}) {
 	if entry.matchLevel(TraceLevel) {
-		entry.Trace(spew.Sprintf(format, args...))
+		entry.Trace(entry.spewConfig().Sprintf(format, args...))
 	}
 }
 
@@ -285,10 +872,13 @@ func (entry *Entry) Errorf(format string, args ...interface{}) {
 }
 
 func (entry *Entry) Fatalf(format string, args ...interface{}) {
+	if entry.noop {
+		return
+	}
 	if entry.matchLevel(FatalLevel) {
 		entry.Fatal(fmt.Sprintf(format, args...))
 	}
-	Exit(1)
+	Exit(entry.Logger.fatalExitCode())
 }
 
 func (entry *Entry) Panicf(format string, args ...interface{}) {
@@ -338,10 +928,13 @@ func (entry *Entry) Errorln(args ...interface{}) {
 }
 
 func (entry *Entry) Fatalln(args ...interface{}) {
+	if entry.noop {
+		return
+	}
 	if entry.matchLevel(FatalLevel) {
 		entry.Fatal(entry.sprintlnn(args...))
 	}
-	Exit(1)
+	Exit(entry.Logger.fatalExitCode())
 }
 
 func (entry *Entry) Panicln(args ...interface{}) {
@@ -356,27 +949,52 @@ func (entry *Entry) Panicln(args ...interface{}) {
 // string allocation, we do the simplest thing.
 func (entry *Entry) sprintlnn(args ...interface{}) string {
 	buf := &bytes.Buffer{}
-	spew.Fdump(buf, args...)
+	entry.spewConfig().Fdump(buf, args...)
 	buf.WriteString("\n")
 	msg := buf.String()
 	return msg[:len(msg)-1]
 }
 
+// spewConfig returns the spew configuration used to dump the arguments of the
+// logging functions, which is spew.Config with the logger's MaxDumpDepth.
+func (entry *Entry) spewConfig() *spew.ConfigState {
+	if entry.Logger == nil || entry.Logger.MaxDumpDepth <= 0 {
+		return &spew.Config
+	}
+	config := spew.Config
+	config.MaxDepth = entry.Logger.MaxDumpDepth
+	return &config
+}
+
+func (entry *Entry) sdump(args ...interface{}) string {
+	if entry.Logger != nil && entry.Logger.ArgJoiner != nil {
+		// The joiner gets a copy so that args doesn't escape, which would make
+		// every call to Info and co allocate, even the discarded ones.
+		return entry.Logger.ArgJoiner(append([]interface{}(nil), args...))
+	}
+	return entry.spewConfig().Sdump(args...)
+}
+
 func (entry *Entry) matchLevel(lv Level) bool {
-	moduleName := DefaultModuleName
-	if entry.Data != nil {
-		if name, ok := entry.Data[ModuleNameKey]; ok {
-			moduleName = name.(string)
-		}
+	if entry.noop {
+		return false
 	}
-	return entry.Logger.level(moduleName) >= lv
+	return entry.Logger.level(entry.ModuleName()).enables(lv)
 }
 
-func (entry *Entry) NewErrorGenerator() *errors.Generator {
-	name, ok := entry.Data[ModuleNameKey].(string)
-	if !ok {
-		name = DefaultModuleName
+// ModuleName returns the module of the entry, DefaultModuleName if it has
+// none. The field may have been set to a non-string value with WithField, it
+// is converted like a non-string key.
+func (entry *Entry) ModuleName() string {
+	name, ok := entry.Data[ModuleNameKey]
+	if !ok || name == nil {
+		return DefaultModuleName
 	}
+	return stringify(name)
+}
+
+func (entry *Entry) NewErrorGenerator() *errors.Generator {
+	name := entry.ModuleName()
 
 	errFields := errors.Fields{}
 	for k, v := range entry.Data {
@@ -0,0 +1,66 @@
+package logrus
+
+import (
+	"fmt"
+	"testing"
+)
+
+// keeps the compiler from optimizing the benchmarked calls away
+var benchmarkEntry *Entry
+
+func BenchmarkEntryWith(b *testing.B) {
+	entry := NewEntry(New()).WithField("foo", "bar")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkEntry = entry.With("key", "value")
+	}
+}
+
+func BenchmarkEntryWithFieldChain(b *testing.B) {
+	logger := New()
+	fields := Fields{"key": "value"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entry := logger.WithFields(fields)
+		for j := 0; j < 9; j++ {
+			entry = entry.WithFields(fields)
+		}
+		benchmarkEntry = entry
+	}
+}
+
+func BenchmarkEntryExtendChain(b *testing.B) {
+	logger := New()
+	fields := Fields{"key": "value"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entry := logger.WithFields(fields)
+		for j := 0; j < 9; j++ {
+			entry = entry.Extend(fields)
+		}
+		benchmarkEntry = entry
+	}
+}
+
+func BenchmarkEntryWithError(b *testing.B) {
+	benchmarkEntryWithError(b, true)
+}
+
+func BenchmarkEntryWithErrorNoStack(b *testing.B) {
+	benchmarkEntryWithError(b, false)
+}
+
+func benchmarkEntryWithError(b *testing.B, stackOnError bool) {
+	logger := New()
+	logger.SetStackOnError(stackOnError)
+	entry := NewEntry(logger)
+	err := fmt.Errorf("test")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkEntry = entry.WithError(err)
+	}
+}
@@ -2,10 +2,15 @@ package logrus
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/yyscamper/errors"
 )
 
 func TestEntryWithError(t *testing.T) {
@@ -75,3 +80,566 @@ func TestEntryPanicf(t *testing.T) {
 	entry := NewEntry(logger)
 	entry.WithField("err", errBoom).Panicf("kaboom %v", true)
 }
+
+func TestEntryMergeStackKeepsMostComplete(t *testing.T) {
+	logger := New()
+	entry := NewEntry(logger).withStack("goroutine 1:\nb()\na()\nmain()")
+
+	// same call path, one frame deeper: the more complete stack wins
+	assert.Equal(t, "goroutine 1:\nc()\nb()\na()\nmain()", entry.mergeStack("goroutine 1:\nc()\nb()\na()\nmain()"))
+	// same call path, one frame shallower: the existing stack is kept
+	assert.Equal(t, "goroutine 1:\nb()\na()\nmain()", entry.mergeStack("goroutine 1:\na()\nmain()"))
+	// an unrelated stack replaces the existing one
+	assert.Equal(t, "goroutine 2:\ny()\nx()\nmain()", entry.mergeStack("goroutine 2:\ny()\nx()\nmain()"))
+}
+
+func newNestedError() *errors.Error {
+	return errors.New("kaboom")
+}
+
+func TestEntryWithStackAndWithErrorHasSingleStacktrace(t *testing.T) {
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+
+	err := newNestedError()
+	var logged *Entry
+	logger.Hooks.Add(&hookFunc{func(entry *Entry) { logged = entry }})
+	NewEntry(logger).WithStack().WithError(err).Error("test")
+
+	stack, ok := logged.Data[StacktraceKey].(string)
+	assert.True(t, ok)
+	assert.Equal(t, err.Stack(), stack, "the error's deeper stack should be the only one kept")
+}
+
+func TestEntryWithErrorStacktraceMinLevel(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithError(fmt.Errorf("expected")).Warn("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "expected", fields[ErrorKey])
+		assert.NotContains(t, fields, StacktraceKey, "warnings should not get the stacktrace")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithError(fmt.Errorf("unexpected")).Error("test")
+	}, func(fields Fields) {
+		assert.Contains(t, fields[StacktraceKey], "TestEntryWithErrorStacktraceMinLevel")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetStacktraceMinLevel(WarnLevel)
+		log.WithError(newNestedError()).Warn("test")
+	}, func(fields Fields) {
+		assert.Contains(t, fields[StacktraceKey], "newNestedError")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetStacktraceMinLevel(PanicLevel)
+		log.WithError(fmt.Errorf("unexpected")).Error("test")
+	}, func(fields Fields) {
+		assert.NotContains(t, fields, StacktraceKey, "only the panics should get the stacktrace")
+	})
+
+	var buffer bytes.Buffer
+	logger := &Logger{Out: &buffer, Formatter: new(JSONFormatter), Hooks: make(LevelHooks), Level: InfoLevel}
+	logger.WithError(fmt.Errorf("unexpected")).Error("test")
+	assert.Contains(t, buffer.String(), `"`+StacktraceKey+`"`, "a logger without a StacktraceMinLevel should log the stacktraces of errors")
+}
+
+func TestEntryWithLenient(t *testing.T) {
+	entry := NewEntry(New())
+
+	data := entry.With("a", 1, "b", 2, "c").Data
+	assert.Equal(t, Fields{"a": 1, "b": 2, "c": nil}, data)
+
+	data = entry.With("a", 1, 42, "answer").Data
+	assert.Equal(t, Fields{"a": 1, "42": "answer"}, data)
+}
+
+func TestEntryWithStrict(t *testing.T) {
+	logger := New()
+	logger.StrictFields = true
+	entry := NewEntry(logger)
+
+	data := entry.With("a", 1, "b", 2, "c").Data
+	assert.Equal(t, 1, data["a"])
+	assert.Equal(t, 2, data["b"])
+	_, ok := data["c"]
+	assert.False(t, ok, "dangling key should not be added")
+	assert.Contains(t, data[FieldErrorKey], "odd number of arguments")
+	assert.Contains(t, data[FieldErrorKey], `"c"`)
+
+	data = entry.With("a", 1, 42, "answer").Data
+	assert.Equal(t, "answer", data["42"])
+	assert.Contains(t, data[FieldErrorKey], "non-string key 42")
+
+	data = entry.With("a", 1, "b", 2).Data
+	_, ok = data[FieldErrorKey]
+	assert.False(t, ok, "well formed calls should not be reported")
+}
+
+func TestEntryWithFieldsIfAbsent(t *testing.T) {
+	base := NewEntry(New()).WithFields(Fields{"env": "prod", "app": "api"})
+
+	merged := base.WithFieldsIfAbsent(Fields{"env": "test", "region": "eu"})
+	assert.Equal(t, Fields{"env": "prod", "app": "api", "region": "eu"}, merged.Data)
+	assert.Equal(t, Fields{"env": "prod", "app": "api"}, base.Data, "the base entry must not be modified")
+}
+
+func TestEntryWithFieldsIfAbsentOnNoopEntry(t *testing.T) {
+	assert.NotPanics(t, func() { New().If(false).WithFieldsIfAbsent(Fields{"a": 1}).Info("x") })
+	assert.NotPanics(t, func() { NewEntry(New()).Discard().WithFieldsIfAbsent(Fields{"a": 1}).Info("x") })
+}
+
+type countingFormatter struct {
+	formatted int
+}
+
+func (f *countingFormatter) Format(entry *Entry) ([]byte, error) {
+	f.formatted++
+	return []byte(entry.Message), nil
+}
+
+type panickingStringer struct{}
+
+func (panickingStringer) String() string {
+	panic("arguments of a discarded entry must not be formatted")
+}
+
+func TestEntryIf(t *testing.T) {
+	var buffer bytes.Buffer
+	formatter := &countingFormatter{}
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = formatter
+
+	logger.If(false).Info("x")
+	logger.If(false).WithFields(Fields{"a": 1}).Infof("%v", panickingStringer{})
+	logger.If(false).WithError(fmt.Errorf("x")).Errorln(panickingStringer{})
+	logger.If(false).Fatal("x")
+	logger.If(false).Panic("x")
+	assert.Equal(t, "", buffer.String())
+	assert.Equal(t, 0, formatter.formatted)
+
+	logger.If(true).Info("x")
+	assert.Equal(t, 1, formatter.formatted)
+	assert.NotEqual(t, "", buffer.String())
+}
+
+func TestEntryLogIf(t *testing.T) {
+	var buffer bytes.Buffer
+	formatter := &countingFormatter{}
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = formatter
+
+	logger.LogIf(false, InfoLevel, panickingStringer{})
+	logger.WithField("a", 1).LogIf(false, ErrorLevel, panickingStringer{})
+	logger.LogIf(false, FatalLevel, "x")
+	logger.LogIf(false, PanicLevel, "x")
+	assert.Equal(t, "", buffer.String())
+	assert.Equal(t, 0, formatter.formatted)
+
+	logger.LogIf(true, WarnLevel, "x")
+	logger.WithField("a", 1).LogIf(true, InfoLevel, "y")
+	assert.Equal(t, 2, formatter.formatted)
+	assert.Panics(t, func() { logger.LogIf(true, PanicLevel, "z") })
+}
+
+func TestEntryIfDoesNotAllocate(t *testing.T) {
+	entry := NewEntry(New())
+	allocs := testing.AllocsPerRun(100, func() {
+		entry.If(false).WithField("a", 1).Info("x")
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func TestEntryMarshalJSON(t *testing.T) {
+	entry := NewEntry(New()).WithFields(Fields{
+		"module": "db",
+		"error":  fmt.Errorf("wild walrus"),
+		"count":  3,
+		"msg":    "clash",
+	})
+	entry.Time = time.Date(2017, 8, 22, 10, 11, 12, 5, time.UTC)
+	entry.Level = WarnLevel
+	entry.Message = "test"
+
+	b, err := json.Marshal(entry)
+	assert.NoError(t, err)
+
+	var fields Fields
+	assert.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, Fields{
+		"time":       "2017-08-22T10:11:12.000000005Z",
+		"level":      "warning",
+		"module":     "db",
+		"msg":        "test",
+		"fields.msg": "clash",
+		"error":      "wild walrus",
+		"count":      3.0,
+	}, fields)
+}
+
+func TestEntryMarshalJSONDefaultModule(t *testing.T) {
+	b, err := json.Marshal(NewEntry(New()))
+	assert.NoError(t, err)
+
+	var fields Fields
+	assert.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, DefaultModuleName, fields["module"])
+	assert.Equal(t, "panic", fields["level"])
+}
+
+func TestEntryWithTime(t *testing.T) {
+	fixed := time.Date(2017, 8, 22, 10, 11, 12, 0, time.UTC)
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithTime(fixed).WithField("a", 1).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "2017-08-22T10:11:12Z", fields["time"])
+	})
+}
+
+func TestLoggerSetClock(t *testing.T) {
+	fixed := time.Date(2017, 8, 22, 10, 11, 12, 0, time.UTC)
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetClock(func() time.Time { return fixed })
+		log.WithField("a", 1).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "2017-08-22T10:11:12Z", fields["time"])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetClock(func() time.Time { return fixed })
+		log.WithTime(fixed.Add(time.Hour)).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "2017-08-22T11:11:12Z", fields["time"], "WithTime should win over the clock")
+	})
+}
+
+func TestEntryWithErrors(t *testing.T) {
+	plain := fmt.Errorf("plain walrus")
+	nested := errors.New("nested walrus")
+	nested.Name = "db"
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithErrors(plain, nil, nested).Error("test")
+	}, func(fields Fields) {
+		list, ok := fields["errors"].([]interface{})
+		if assert.True(t, ok, "errors should be rendered as an array") && assert.Len(t, list, 2) {
+			assert.Equal(t, map[string]interface{}{"error": "plain walrus"}, list[0])
+			item := list[1].(map[string]interface{})
+			assert.Equal(t, "nested walrus", item["error"])
+			assert.Equal(t, "db", item["module"])
+			assert.Equal(t, nested.Stack(), item["stacktrace"])
+		}
+	})
+}
+
+type retainingHook struct {
+	retained [][]byte
+}
+
+func (h *retainingHook) Levels() []Level { return AllLevels }
+
+func (h *retainingHook) Fire(entry *Entry) error {
+	b, err := entry.Bytes()
+	h.retained = append(h.retained, b)
+	return err
+}
+
+func TestEntryBytes(t *testing.T) {
+	logger := New()
+	logger.Formatter = &JSONFormatter{DisableTimestamp: true}
+	entry := logger.WithField("a", 1)
+	entry.Message = "test"
+
+	b, err := entry.Bytes()
+	assert.NoError(t, err)
+	s, err := entry.String()
+	assert.NoError(t, err)
+	assert.Equal(t, s, string(b))
+
+	// formatted while log() has set the pooled buffer
+	entry.Buffer = &bytes.Buffer{}
+	b, err = entry.Bytes()
+	assert.NoError(t, err)
+	entry.Buffer.WriteString("overwritten")
+	assert.Equal(t, s, string(b))
+}
+
+func TestEntryBytesRetainedByHook(t *testing.T) {
+	hook := &retainingHook{}
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	logger.Formatter = &JSONFormatter{DisableTimestamp: true}
+	logger.Hooks.Add(hook)
+
+	logger.Info("first")
+	logger.Info("second entry, a bit longer")
+	assert.Equal(t, `{"level":"info","msg":"first"}`+"\n", string(hook.retained[0]))
+	assert.Equal(t, `{"level":"info","msg":"second entry, a bit longer"}`+"\n", string(hook.retained[1]))
+}
+
+type moduleStruct struct {
+	Name string
+}
+
+func TestEntryNonStringModule(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.SetModuleLevel("123", DebugLevel)
+
+	assert.NotPanics(t, func() {
+		logger.WithField(ModuleNameKey, 123).Debug("int module")
+		logger.WithField(ModuleNameKey, moduleStruct{"db"}).Info("struct module")
+		logger.WithField(ModuleNameKey, nil).Info("nil module")
+	})
+	assert.Contains(t, buffer.String(), "int module", "the int module should use the level of its string form")
+	assert.Contains(t, buffer.String(), "struct module")
+	assert.Contains(t, buffer.String(), "nil module")
+
+	assert.NotPanics(t, func() {
+		assert.NotNil(t, logger.WithField(ModuleNameKey, 123).NewErrorGenerator())
+		assert.NotNil(t, logger.WithField(ModuleNameKey, moduleStruct{"db"}).NewErrorGenerator())
+	})
+}
+
+func TestEntrySortedKeys(t *testing.T) {
+	logger := New()
+	entry := logger.WithFields(Fields{"b": 2, "request_id": "42", "a": 1})
+	assert.Equal(t, []string{"a", "b", "request_id"}, entry.SortedKeys())
+
+	logger.FieldOrder = []string{"request_id", "missing", "request_id"}
+	assert.Equal(t, []string{"request_id", "a", "b"}, entry.SortedKeys())
+}
+
+func TestEntryExtend(t *testing.T) {
+	logger := New()
+	base := NewEntry(logger)
+
+	// not owned, the base entry must not be modified
+	entry := base.Extend(Fields{"a": 1})
+	assert.NotEqual(t, base, entry)
+	assert.Empty(t, base.Data)
+
+	derived := logger.WithField("a", 1)
+	assert.False(t, derived.Extend(Fields{"b": 2}) == derived, "the result of WithField can be shared, it is not owned")
+	assert.Equal(t, Fields{"a": 1}, derived.Data)
+
+	extended := entry.Extend(Fields{"b": 2}).Extend(Fields{"c": 3})
+	assert.True(t, entry == extended, "an owned entry should be extended in place")
+	assert.Equal(t, Fields{"a": 1, "b": 2, "c": 3}, extended.Data)
+
+	ctx := extended.WithContext(context.Background())
+	assert.False(t, ctx.Extend(Fields{"d": 4}) == ctx, "entries sharing their data are not owned")
+	assert.Equal(t, Fields{"a": 1, "b": 2, "c": 3}, extended.Data)
+}
+
+func TestEntryExtendSharedBase(t *testing.T) {
+	shared := New().WithFields(Fields{"a": 1})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			entry := shared.Extend(Fields{"b": i}).Extend(Fields{"c": i})
+			assert.Equal(t, Fields{"a": 1, "b": i, "c": i}, entry.Data)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, Fields{"a": 1}, shared.Data)
+}
+
+func TestEntryLogComputedLevel(t *testing.T) {
+	level := func(err error) Level {
+		if err != nil {
+			return ErrorLevel
+		}
+		return InfoLevel
+	}
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithField("attempt", 3).Logf(level(fmt.Errorf("boom")), "retry %d failed", 3)
+	}, func(fields Fields) {
+		assert.Equal(t, "retry 3 failed", fields["msg"])
+		assert.Equal(t, "error", fields["level"])
+		assert.Equal(t, float64(3), fields["attempt"])
+	})
+
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Level = WarnLevel
+	logger.Log(level(nil), "ignored")
+	assert.Equal(t, 0, buffer.Len())
+}
+
+func TestEntryLogFatalLevelExits(t *testing.T) {
+	code := -1
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	osExit = func(c int) { code = c }
+
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = new(JSONFormatter)
+	logger.WithField("k", "v").Log(FatalLevel, "fatal ", "error")
+
+	assert.Equal(t, 1, code)
+	var fields Fields
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &fields))
+	assert.Equal(t, "fatal error", fields["msg"])
+	assert.Equal(t, "fatal", fields["level"])
+}
+
+func TestEntryLogPanicLevelPanics(t *testing.T) {
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	assert.Panics(t, func() { logger.Logf(PanicLevel, "kaboom %d", 1) })
+}
+
+type hookFunc struct {
+	fire func(*Entry)
+}
+
+func (h *hookFunc) Levels() []Level { return AllLevels }
+func (h *hookFunc) Fire(entry *Entry) error {
+	h.fire(entry)
+	return nil
+}
+
+type healthCheckFilter struct{}
+
+func (healthCheckFilter) Levels() []Level { return AllLevels }
+func (healthCheckFilter) Fire(entry *Entry) error {
+	if entry.Data["path"] == "/healthz" {
+		entry.Drop()
+	}
+	return nil
+}
+
+func TestEntryDropByHook(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Hooks.Add(healthCheckFilter{})
+
+	logger.WithField("path", "/healthz").Info("request")
+	assert.Equal(t, 0, buffer.Len())
+
+	logger.WithField("path", "/users").Info("request")
+	assert.Contains(t, buffer.String(), "/users")
+}
+
+func TestEntryDropKey(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Hooks.Add(&hookFunc{func(entry *Entry) { entry.Data[DropKey] = true }})
+
+	logger.WithField("a", 1).Info("dropped")
+	assert.Equal(t, 0, buffer.Len())
+}
+
+func TestEntryDropStillPanics(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Hooks.Add(&hookFunc{func(entry *Entry) { entry.Drop() }})
+
+	assert.Panics(t, func() { logger.Panic("dropped") })
+	assert.Equal(t, 0, buffer.Len())
+}
+
+func TestEntryDiscard(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+
+	entry := logger.WithField("a", 1)
+	entry.Discard().WithField("b", 2).Error("discarded")
+	assert.Equal(t, 0, buffer.Len())
+}
+
+func TestEntryWithFieldf(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		base := log.WithField("a", 1)
+		entry := base.WithFieldf("url", "%s/%d", "http://example.com", 42)
+		assert.NotContains(t, base.Data, "url", "the entry should be derived")
+		entry.Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "http://example.com/42", fields["url"])
+		assert.Equal(t, float64(1), fields["a"])
+	})
+}
+
+// Run with -race, the hooks and the formatter modify the data of entries
+// derived from a base entry shared by all the goroutines.
+func TestEntrySharedAcrossGoroutines(t *testing.T) {
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	logger.Hooks.Add(&hookFunc{func(entry *Entry) { entry.Data["hooked"] = true }})
+	base := logger.WithFields(Fields{"time": "clashes", "service": "api"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			base.WithField("i", i).Info("derived")
+			base.Info("base")
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, Fields{"time": "clashes", "service": "api"}, base.Data)
+}
+
+func TestFatalExitCode(t *testing.T) {
+	code := -1
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	osExit = func(c int) { code = c }
+
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	logger.Fatal("default")
+	assert.Equal(t, 1, code)
+
+	logger.SetFatalExitCode(3)
+	logger.Fatalf("configured %d", 3)
+	assert.Equal(t, 3, code)
+	logger.WithField("a", 1).Fatalln("configured")
+	assert.Equal(t, 3, code)
+
+	logger.WithField("a", 1).FatalWithCode(75, "per call")
+	assert.Equal(t, 75, code)
+	assert.Contains(t, logger.Out.(*bytes.Buffer).String(), "per call")
+}
+
+func TestEntryWithErrorWithoutStackOnError(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetStackOnError(false)
+		log.WithError(fmt.Errorf("plain")).Error("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "plain", fields[ErrorKey])
+		assert.NotContains(t, fields, StacktraceKey)
+	})
+}
+
+func TestEntryWithFieldOnce(t *testing.T) {
+	entry := NewEntry(New()).WithFieldOnce("user", "first")
+	assert.Equal(t, "first", entry.Data["user"])
+
+	again := entry.WithFieldOnce("user", "second")
+	assert.True(t, again == entry, "the entry should be returned as is")
+	assert.Equal(t, "first", again.Data["user"])
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithField("user", "first").WithFieldOnce("user", "second").WithFieldOnce("id", 1).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "first", fields["user"])
+		assert.Equal(t, float64(1), fields["id"])
+	})
+}
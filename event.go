@@ -0,0 +1,39 @@
+package logrus
+
+import "sync"
+
+// Defines the key used for the event id of entries logged with Event.
+var EventIDKey = "event_id"
+
+var (
+	events   = map[string]string{}
+	eventsMu sync.RWMutex
+)
+
+// RegisterEvent adds an event to the catalog used by Entry.Event. The template
+// is a format string used to build the message from the arguments passed to
+// Event. Registering an existing id replaces its template.
+func RegisterEvent(id string, template string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	events[id] = template
+}
+
+func lookupEvent(id string) (string, bool) {
+	eventsMu.RLock()
+	defer eventsMu.RUnlock()
+	template, ok := events[id]
+	return template, ok
+}
+
+// Event logs a registered event at InfoLevel. The message is built from the
+// event's template and args, and the id is added using the key EventIDKey. An
+// id which was not registered with RegisterEvent is logged as a warning.
+func (entry *Entry) Event(id string, args ...interface{}) {
+	template, ok := lookupEvent(id)
+	if !ok {
+		entry.WithField(EventIDKey, id).Warnf("unknown event id %q", id)
+		return
+	}
+	entry.WithField(EventIDKey, id).Infof(template, args...)
+}
@@ -0,0 +1,59 @@
+package logrus
+
+import "fmt"
+
+// LogEvent is the structured form of an entry passed to the event sink, see
+// SetEventSink. It mirrors a protobuf message, so it only holds scalar values
+// and a map of strings, and can be copied into the generated type of a
+// binary log pipeline field by field.
+type LogEvent struct {
+	// Time of the entry in nanoseconds since the Unix epoch.
+	TimeUnixNano int64
+	Level        string
+	Message      string
+	// Fields of the entry, formatted with fmt.Sprint, or Error for errors.
+	Fields map[string]string
+}
+
+// SetEventSink makes the logger pass the entries to sink as LogEvents instead
+// of formatting them and writing them to Out, e.g. to serialize them as
+// protobuf. The hooks are still fired and the sinks added with AddSink still
+// written to. Calls to sink are serialized and its errors are reported to the
+// ErrorHandler. Passing nil restores the Formatter.
+func (logger *Logger) SetEventSink(sink func(LogEvent) error) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.EventSink = sink
+}
+
+// newLogEvent converts the entry to a LogEvent.
+func newLogEvent(entry *Entry) LogEvent {
+	fields := make(map[string]string, len(entry.Data))
+	for k, v := range entry.Data {
+		switch v := v.(type) {
+		case string:
+			fields[k] = v
+		case error:
+			fields[k] = v.Error()
+		default:
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	return LogEvent{
+		TimeUnixNano: entry.Time.UnixNano(),
+		Level:        entry.Level.String(),
+		Message:      entry.Message,
+		Fields:       fields,
+	}
+}
+
+// sendEvent passes the entry to the event sink of the logger.
+func (entry *Entry) sendEvent(sink func(LogEvent) error) {
+	event := newLogEvent(entry)
+	entry.Logger.mu.Lock()
+	err := entry.Logger.callEventSink(sink, event)
+	entry.Logger.mu.Unlock()
+	if err != nil {
+		entry.Logger.handleError("Failed to send event, %v\n", err)
+	}
+}
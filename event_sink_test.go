@@ -0,0 +1,71 @@
+package logrus
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSink(t *testing.T) {
+	var buffer bytes.Buffer
+	var events []LogEvent
+	logger := New()
+	logger.Out = &buffer
+	logger.SetEventSink(func(event LogEvent) error {
+		events = append(events, event)
+		return nil
+	})
+
+	now := time.Unix(1500000000, 42)
+	logger.WithTime(now).WithFields(Fields{
+		"str":   "value",
+		"int":   1,
+		"error": errors.New("wrong"),
+	}).Warn("test")
+
+	assert.Equal(t, "", buffer.String(), "the formatter should be bypassed")
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, LogEvent{
+			TimeUnixNano: now.UnixNano(),
+			Level:        "warning",
+			Message:      "test",
+			Fields:       map[string]string{"str": "value", "int": "1", "error": "wrong"},
+		}, events[0])
+	}
+
+	logger.SetEventSink(nil)
+	logger.Info("test")
+	assert.Contains(t, buffer.String(), "msg=test")
+}
+
+func TestEventSinkError(t *testing.T) {
+	var handled error
+	logger := New()
+	logger.SetErrorHandler(func(err error) { handled = err })
+	logger.SetEventSink(func(LogEvent) error { return errors.New("sink failed") })
+
+	logger.Info("test")
+	assert.EqualError(t, handled, "sink failed")
+}
+
+func TestEventSinkKeepsSinks(t *testing.T) {
+	var buffer, sink bytes.Buffer
+	sent := 0
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	logger.AddSink(&sink, nil, InfoLevel)
+	logger.SetEventSink(func(LogEvent) error {
+		sent++
+		return nil
+	})
+
+	logger.Info("test")
+
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, "", buffer.String())
+	assert.Equal(t, "level=info msg=test \n", sink.String(), "the sinks should still be written to")
+}
@@ -0,0 +1,30 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisteredEvent(t *testing.T) {
+	RegisterEvent("user.login", "user %s logged in from %s")
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithField("foo", "bar").Event("user.login", "walrus", "10.0.0.1")
+	}, func(fields Fields) {
+		assert.Equal(t, "user walrus logged in from 10.0.0.1", fields["msg"])
+		assert.Equal(t, "info", fields["level"])
+		assert.Equal(t, "user.login", fields[EventIDKey])
+		assert.Equal(t, "bar", fields["foo"])
+	})
+}
+
+func TestUnregisteredEvent(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		NewEntry(log).Event("no.such.event", 42)
+	}, func(fields Fields) {
+		assert.Equal(t, "warning", fields["level"])
+		assert.Equal(t, "no.such.event", fields[EventIDKey])
+		assert.Contains(t, fields["msg"], "unknown event id")
+	})
+}
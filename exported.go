@@ -1,7 +1,12 @@
 package logrus
 
 import (
+	"context"
 	"io"
+	"regexp"
+	"time"
+
+	"github.com/yyscamper/errors"
 )
 
 var (
@@ -27,18 +32,136 @@ func SetFormatter(formatter Formatter) {
 	std.Formatter = formatter
 }
 
-// SetStackOnError sets the standard logger level.
+// SetStackOnError sets whether WithError captures the stacktrace of plain
+// errors on the standard logger.
 func SetStackOnError(enable bool) {
-	std.mu.Lock()
-	defer std.mu.Unlock()
-	std.StackOnError = enable
+	std.SetStackOnError(enable)
+}
+
+// SetReportCaller sets whether the standard logger attaches the caller.
+func SetReportCaller(enable bool) {
+	std.SetReportCaller(enable)
+}
+
+// SetReportCallerDepth sets the number of extra frames skipped by the standard
+// logger when reporting the caller.
+func SetReportCallerDepth(depth int) {
+	std.SetReportCallerDepth(depth)
+}
+
+// SetContextExtractor sets the function used by the standard logger to extract
+// fields from the context of an entry.
+func SetContextExtractor(extractor func(context.Context) Fields) {
+	std.SetContextExtractor(extractor)
+}
+
+// AddSecretPattern adds a pattern of secrets masked by the standard logger.
+func AddSecretPattern(pattern *regexp.Regexp) {
+	std.AddSecretPattern(pattern)
+}
+
+// SetErrorHandler sets the function called by the standard logger when a
+// hook, its formatter or its output fails.
+func SetErrorHandler(handler func(error)) {
+	std.SetErrorHandler(handler)
+}
+
+// SetAssertPanics makes the failed assertions of the standard logger panic
+// instead of being logged at ErrorLevel.
+func SetAssertPanics(enable bool) {
+	std.SetAssertPanics(enable)
+}
+
+// SetPoolEntries makes the standard logger reuse the copies of the entries
+// made when logging.
+func SetPoolEntries(enable bool) {
+	std.SetPoolEntries(enable)
+}
+
+// SetFloatPrecision sets the number of digits after the decimal point of the
+// WithFloat values without a precision on the standard logger.
+func SetFloatPrecision(prec int) {
+	std.SetFloatPrecision(prec)
+}
+
+// AddSink makes the standard logger also write the entries at minLevel or a
+// more severe level to w, formatted by f.
+func AddSink(w io.Writer, f Formatter, minLevel Level) {
+	std.AddSink(w, f, minLevel)
+}
+
+// SetEventSink makes the standard logger pass the entries to sink instead of
+// formatting and writing them.
+func SetEventSink(sink func(LogEvent) error) {
+	std.SetEventSink(sink)
+}
+
+// SetMaxDumpDepth limits how deep the standard logger dumps nested values.
+func SetMaxDumpDepth(depth int) {
+	std.SetMaxDumpDepth(depth)
+}
+
+// SetArgJoiner sets the function building the messages of the standard logger
+// from the arguments of the logging functions.
+func SetArgJoiner(joiner func(args []interface{}) string) {
+	std.SetArgJoiner(joiner)
+}
+
+// SetClock sets the function returning the time of the entries of the
+// standard logger.
+func SetClock(clock func() time.Time) {
+	std.SetClock(clock)
+}
+
+// SetMaxMessageBytes limits the size of the messages of the standard logger.
+func SetMaxMessageBytes(max int) {
+	std.SetMaxMessageBytes(max)
+}
+
+// SetMaxBinaryBytes limits the number of bytes formatted of the WithBinary
+// fields of the standard logger.
+func SetMaxBinaryBytes(max int) {
+	std.SetMaxBinaryBytes(max)
+}
+
+// SetMaxFields limits the number of fields of the entries of the standard
+// logger.
+func SetMaxFields(max int) {
+	std.SetMaxFields(max)
+}
+
+// SetLevelSchedule sets the windows switching the level of the standard logger
+// during the day.
+func SetLevelSchedule(schedule []ScheduleEntry) {
+	std.SetLevelSchedule(schedule)
+}
+
+// SetFieldsFromEnv adds fields read from environment variables to every entry
+// of the standard logger, the map goes from the field names to the variables.
+func SetFieldsFromEnv(vars map[string]string) {
+	std.SetFieldsFromEnv(vars)
+}
+
+// SetProcessFields sets whether the standard logger attaches the hostname,
+// pid and version.
+func SetProcessFields(enable bool) {
+	std.SetProcessFields(enable)
+}
+
+// SetVersion sets the version attached by the standard logger with the
+// process fields.
+func SetVersion(version string) {
+	std.SetVersion(version)
+}
+
+// SetReportGoroutineID sets whether the standard logger attaches the goroutine id.
+func SetReportGoroutineID(enable bool) {
+	std.SetReportGoroutineID(enable)
 }
 
 // SetLevel sets the standard logger level.
 func SetLevel(level Level) {
-	std.mu.Lock()
-	defer std.mu.Unlock()
-	std.setLevel(level)
+	std.SetLevel(level)
 }
 
 // SetModuleLevel set the logging level for a specified module
@@ -46,6 +169,36 @@ func SetModuleLevel(moduleName string, level Level) {
 	std.SetModuleLevel(moduleName, level)
 }
 
+// Boost sets the level of a module of the standard logger until the returned
+// function is called, see Logger.Boost.
+func Boost(moduleName string, level Level) (restore func()) {
+	return std.Boost(moduleName, level)
+}
+
+// SetFallbackFormatter sets the formatter used for the entries the formatter of
+// the standard logger fails to format.
+func SetFallbackFormatter(formatter Formatter) {
+	std.SetFallbackFormatter(formatter)
+}
+
+// SetFatalExitCode sets the code the program exits with after a Fatal entry of
+// the standard logger.
+func SetFatalExitCode(code int) {
+	std.SetFatalExitCode(code)
+}
+
+// SetStacktraceMinLevel sets the least severe level at which the standard
+// logger logs the stacktraces of the errors added with WithError.
+func SetStacktraceMinLevel(level Level) {
+	std.SetStacktraceMinLevel(level)
+}
+
+// SetModuleOutput sets the writer of the entries of a module of the standard
+// logger.
+func SetModuleOutput(moduleName string, w io.Writer) {
+	std.SetModuleOutput(moduleName, w)
+}
+
 // SetModuleLevelString set the logging levels for modules in a convience way
 //
 // For example: Set module "foo" in debug level, and "bar" in error level:
@@ -72,9 +225,18 @@ func GetAllModuleLevels() map[string]Level {
 
 // GetLevel returns the standard logger level.
 func GetLevel() Level {
-	std.mu.Lock()
-	defer std.mu.Unlock()
-	return std.level()
+	return std.GetLevel()
+}
+
+// If returns an entry from the standard logger when cond is true and
+// otherwise an entry on which the logging functions do nothing, see Entry.If.
+func If(cond bool) *Entry {
+	return std.If(cond)
+}
+
+// Flush flushes the standard logger, see Logger.Flush.
+func Flush() error {
+	return std.Flush()
 }
 
 // AddHook adds a hook to the standard logger hooks.
@@ -89,11 +251,51 @@ func NewModule(moduleName string) *Entry {
 	return WithField(ModuleNameKey, moduleName)
 }
 
+// WithModule creates a named entry from the standard logger, like NewModule.
+func WithModule(moduleName string) *Entry {
+	return WithField(ModuleNameKey, moduleName)
+}
+
+// WithStack creates an entry from the standard logger and adds the current
+// stacktrace to it, using the value defined in StacktraceKey as key.
+func WithStack() *Entry {
+	return NewEntry(std).withStack(errors.Stack(1))
+}
+
+// With creates an entry from the standard logger and adds key/value pairs to
+// it, see Entry.With.
+func With(key string, value interface{}, extras ...interface{}) *Entry {
+	return NewEntry(std).With(key, value, extras...)
+}
+
 // WithError creates an entry from the standard logger and adds an error to it, using the value defined in ErrorKey as key.
 func WithError(err error) *Entry {
 	return std.WithError(err)
 }
 
+// WithErrors creates an entry from the standard logger and adds several errors
+// to it, using the value defined in ErrorsKey as key.
+func WithErrors(errs ...error) *Entry {
+	return std.WithErrors(errs...)
+}
+
+// WithContext creates an entry from the standard logger and adds a context to it.
+func WithContext(ctx context.Context) *Entry {
+	return std.WithContext(ctx)
+}
+
+// WithRequestID creates an entry from the standard logger and adds the id of
+// the request being served to it, using the value defined in RequestIDKey as
+// key.
+func WithRequestID(id string) *Entry {
+	return std.WithRequestID(id)
+}
+
+// WithTime creates an entry from the standard logger and sets its time.
+func WithTime(t time.Time) *Entry {
+	return std.WithTime(t)
+}
+
 // WithField creates an entry from the standard logger and adds a field to
 // it. If you want multiple fields, use `WithFields`.
 //
@@ -103,6 +305,24 @@ func WithField(key string, value interface{}) *Entry {
 	return std.WithField(key, value)
 }
 
+// WithOrderedFields creates an entry from the standard logger and adds fields
+// output in the order they are given.
+func WithOrderedFields(fields OrderedFields) *Entry {
+	return std.WithOrderedFields(fields)
+}
+
+// Assert logs msg at ErrorLevel on the standard logger, or panics, when cond
+// is false.
+func Assert(cond bool, msg string) *Entry {
+	return std.Assert(cond, msg)
+}
+
+// WithFieldf creates an entry from the standard logger and adds a field with
+// a value formatted according to a format specifier.
+func WithFieldf(key string, format string, args ...interface{}) *Entry {
+	return std.WithFieldf(key, format, args...)
+}
+
 // WithFields creates an entry from the standard logger and adds multiple
 // fields to it. This is simply a helper for `WithField`, invoking it
 // once for each field.
@@ -113,34 +333,69 @@ func WithFields(fields Fields) *Entry {
 	return std.WithFields(fields)
 }
 
+// Log logs a message at a level chosen at runtime on the standard logger.
+func Log(level Level, args ...interface{}) {
+	std.Log(level, args...)
+}
+
+// LogIf logs a message at a level chosen at runtime on the standard logger
+// when cond is true.
+func LogIf(cond bool, level Level, args ...interface{}) {
+	std.LogIf(cond, level, args...)
+}
+
+// Logf logs a message at a level chosen at runtime on the standard logger.
+func Logf(level Level, format string, args ...interface{}) {
+	std.Logf(level, format, args...)
+}
+
+// Trace logs a message at level Trace on the standard logger.
+func Trace(args ...interface{}) {
+	if std.level().enables(TraceLevel) {
+		std.Trace(args...)
+	}
+}
+
 // Debug logs a message at level Debug on the standard logger.
 func Debug(args ...interface{}) {
-	std.Debug(args...)
+	if std.level().enables(DebugLevel) {
+		std.Debug(args...)
+	}
 }
 
 // Print logs a message at level Info on the standard logger.
 func Print(args ...interface{}) {
-	std.Print(args...)
+	if std.level().enables(InfoLevel) {
+		std.Print(args...)
+	}
 }
 
 // Info logs a message at level Info on the standard logger.
 func Info(args ...interface{}) {
-	std.Info(args...)
+	if std.level().enables(InfoLevel) {
+		std.Info(args...)
+	}
 }
 
 // Warn logs a message at level Warn on the standard logger.
 func Warn(args ...interface{}) {
-	std.Warn(args...)
+	if std.level().enables(WarnLevel) {
+		std.Warn(args...)
+	}
 }
 
 // Warning logs a message at level Warn on the standard logger.
 func Warning(args ...interface{}) {
-	std.Warning(args...)
+	if std.level().enables(WarnLevel) {
+		std.Warning(args...)
+	}
 }
 
 // Error logs a message at level Error on the standard logger.
 func Error(args ...interface{}) {
-	std.Error(args...)
+	if std.level().enables(ErrorLevel) {
+		std.Error(args...)
+	}
 }
 
 // Panic logs a message at level Panic on the standard logger.
@@ -153,34 +408,53 @@ func Fatal(args ...interface{}) {
 	std.Fatal(args...)
 }
 
+// Tracef logs a message at level Trace on the standard logger.
+func Tracef(format string, args ...interface{}) {
+	if std.level().enables(TraceLevel) {
+		std.Tracef(format, args...)
+	}
+}
+
 // Debugf logs a message at level Debug on the standard logger.
 func Debugf(format string, args ...interface{}) {
-	std.Debugf(format, args...)
+	if std.level().enables(DebugLevel) {
+		std.Debugf(format, args...)
+	}
 }
 
 // Printf logs a message at level Info on the standard logger.
 func Printf(format string, args ...interface{}) {
-	std.Printf(format, args...)
+	if std.level().enables(InfoLevel) {
+		std.Printf(format, args...)
+	}
 }
 
 // Infof logs a message at level Info on the standard logger.
 func Infof(format string, args ...interface{}) {
-	std.Infof(format, args...)
+	if std.level().enables(InfoLevel) {
+		std.Infof(format, args...)
+	}
 }
 
 // Warnf logs a message at level Warn on the standard logger.
 func Warnf(format string, args ...interface{}) {
-	std.Warnf(format, args...)
+	if std.level().enables(WarnLevel) {
+		std.Warnf(format, args...)
+	}
 }
 
 // Warningf logs a message at level Warn on the standard logger.
 func Warningf(format string, args ...interface{}) {
-	std.Warningf(format, args...)
+	if std.level().enables(WarnLevel) {
+		std.Warningf(format, args...)
+	}
 }
 
 // Errorf logs a message at level Error on the standard logger.
 func Errorf(format string, args ...interface{}) {
-	std.Errorf(format, args...)
+	if std.level().enables(ErrorLevel) {
+		std.Errorf(format, args...)
+	}
 }
 
 // Panicf logs a message at level Panic on the standard logger.
@@ -193,34 +467,53 @@ func Fatalf(format string, args ...interface{}) {
 	std.Fatalf(format, args...)
 }
 
+// Traceln logs a message at level Trace on the standard logger.
+func Traceln(args ...interface{}) {
+	if std.level().enables(TraceLevel) {
+		std.Traceln(args...)
+	}
+}
+
 // Debugln logs a message at level Debug on the standard logger.
 func Debugln(args ...interface{}) {
-	std.Debugln(args...)
+	if std.level().enables(DebugLevel) {
+		std.Debugln(args...)
+	}
 }
 
 // Println logs a message at level Info on the standard logger.
 func Println(args ...interface{}) {
-	std.Println(args...)
+	if std.level().enables(InfoLevel) {
+		std.Println(args...)
+	}
 }
 
 // Infoln logs a message at level Info on the standard logger.
 func Infoln(args ...interface{}) {
-	std.Infoln(args...)
+	if std.level().enables(InfoLevel) {
+		std.Infoln(args...)
+	}
 }
 
 // Warnln logs a message at level Warn on the standard logger.
 func Warnln(args ...interface{}) {
-	std.Warnln(args...)
+	if std.level().enables(WarnLevel) {
+		std.Warnln(args...)
+	}
 }
 
 // Warningln logs a message at level Warn on the standard logger.
 func Warningln(args ...interface{}) {
-	std.Warningln(args...)
+	if std.level().enables(WarnLevel) {
+		std.Warningln(args...)
+	}
 }
 
 // Errorln logs a message at level Error on the standard logger.
 func Errorln(args ...interface{}) {
-	std.Errorln(args...)
+	if std.level().enables(ErrorLevel) {
+		std.Errorln(args...)
+	}
 }
 
 // Panicln logs a message at level Panic on the standard logger.
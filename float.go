@@ -0,0 +1,75 @@
+package logrus
+
+import (
+	"math"
+	"strconv"
+)
+
+// Float is a float field added with WithFloat, formatted with a fixed number
+// of digits after the decimal point by all the formatters. NaN and infinities
+// are formatted as the strings "NaN", "+Inf" and "-Inf", also in JSON, which
+// has no representation for them.
+type Float struct {
+	Value float64
+	// Digits after the decimal point, the shortest representation of the value
+	// if the precision is negative.
+	Precision int
+}
+
+func (f Float) String() string {
+	switch {
+	case math.IsNaN(f.Value):
+		return "NaN"
+	case math.IsInf(f.Value, 1):
+		return "+Inf"
+	case math.IsInf(f.Value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f.Value, 'f', f.Precision, 64)
+}
+
+// MarshalJSON writes the value as a JSON number, or a string for NaN and the
+// infinities.
+func (f Float) MarshalJSON() ([]byte, error) {
+	s := f.String()
+	if math.IsNaN(f.Value) || math.IsInf(f.Value, 0) {
+		return []byte(strconv.Quote(s)), nil
+	}
+	return []byte(s), nil
+}
+
+// Add a float field to the Entry, formatted with prec digits after the
+// decimal point, `WithFloat("ratio", 3.14159, 2)` is logged as 3.14. A
+// negative prec uses the logger's FloatPrecision, see SetFloatPrecision.
+func (entry *Entry) WithFloat(key string, value float64, prec int) *Entry {
+	if entry.noop {
+		return entry
+	}
+	if prec < 0 {
+		prec = entry.Logger.floatPrecision()
+	}
+	return entry.withOne(key, Float{Value: value, Precision: prec})
+}
+
+// NoDecimals is the precision of SetFloatPrecision formatting the values
+// without decimals, like `WithFloat(key, value, 0)`.
+const NoDecimals = -1
+
+// SetFloatPrecision sets the number of digits after the decimal point of the
+// values added with WithFloat without a precision. 0, the default, formats
+// them with the shortest representation, NoDecimals without decimals.
+func (logger *Logger) SetFloatPrecision(prec int) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.FloatPrecision = prec
+}
+
+func (logger *Logger) floatPrecision() int {
+	switch {
+	case logger.FloatPrecision == 0:
+		return -1
+	case logger.FloatPrecision < 0:
+		return 0
+	}
+	return logger.FloatPrecision
+}
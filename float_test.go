@@ -0,0 +1,66 @@
+package logrus
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFloatPrecision(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithField("x", 0).WithFloat("pi", math.Pi, 2).WithFloat("zero", 1.5, 0).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, 3.14, fields["pi"])
+		assert.Equal(t, float64(2), fields["zero"])
+	})
+
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	NewEntry(logger).WithFloat("pi", math.Pi, 3).Info("test")
+	assert.Equal(t, "level=info msg=test pi=3.142 \n", buffer.String())
+}
+
+func TestWithFloatDefaultPrecision(t *testing.T) {
+	logger := New()
+	assert.Equal(t, "0.1", NewEntry(logger).WithFloat("f", 0.1, -1).Data["f"].(Float).String())
+
+	logger.SetFloatPrecision(4)
+	assert.Equal(t, "0.1000", NewEntry(logger).WithFloat("f", 0.1, -1).Data["f"].(Float).String())
+	assert.Equal(t, "0.1", NewEntry(logger).WithFloat("f", 0.1, 1).Data["f"].(Float).String())
+
+	logger.SetFloatPrecision(NoDecimals)
+	assert.Equal(t, "2", NewEntry(logger).WithFloat("f", 1.5, -1).Data["f"].(Float).String())
+	logger.SetFloatPrecision(0)
+	assert.Equal(t, "1.5", NewEntry(logger).WithFloat("f", 1.5, -1).Data["f"].(Float).String())
+
+	literal := &Logger{Out: ioutil.Discard, Formatter: new(TextFormatter), Hooks: make(LevelHooks), Level: InfoLevel}
+	assert.Equal(t, "1.5", NewEntry(literal).WithFloat("f", 1.5, -1).Data["f"].(Float).String(), "a struct literal logger should use the shortest representation")
+}
+
+func TestWithFloatNaNAndInf(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.WithField("x", 0).
+			WithFloat("nan", math.NaN(), 2).
+			WithFloat("inf", math.Inf(1), 2).
+			WithFloat("ninf", math.Inf(-1), 2).
+			Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "NaN", fields["nan"])
+		assert.Equal(t, "+Inf", fields["inf"])
+		assert.Equal(t, "-Inf", fields["ninf"])
+	})
+
+	formatter := &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	b, err := formatter.Format(NewEntry(New()).WithFloat("nan", math.NaN(), 2))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "nan=NaN")
+}
+
+func TestWithFloatOnNoopEntry(t *testing.T) {
+	assert.NotPanics(t, func() { New().If(false).WithFloat("f", 1, -1).Info("test") })
+}
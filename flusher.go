@@ -0,0 +1,49 @@
+package logrus
+
+// Flusher is implemented by writers, hooks and formatters that buffer entries
+// and need to write them out before the program exits.
+type Flusher interface {
+	Flush() error
+}
+
+func init() {
+	RegisterExitHandler(func() { std.Flush() })
+}
+
+// Flush flushes the outputs, the hooks and the formatter of the logger if they
+// implement Flusher, and returns the first error encountered. The standard
+// logger is flushed by the exit handlers, other loggers can be registered
+// with `RegisterExitHandler(func() { logger.Flush() })`.
+func (logger *Logger) Flush() error {
+	var firstErr error
+	flush := func(v interface{}) {
+		if f, ok := v.(Flusher); ok {
+			if err := f.Flush(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	logger.mu.Lock()
+	flush(logger.Out)
+	for _, out := range logger.ModuleOutputs {
+		flush(out)
+	}
+	sinks := logger.Sinks
+	for _, sink := range sinks {
+		flush(sink.Out)
+	}
+	// the hooks and formatters are flushed unlocked, they may log
+	hooks := logger.uniqueHooks()
+	formatter := logger.Formatter
+	logger.mu.Unlock()
+
+	for _, hook := range hooks {
+		flush(hook)
+	}
+	flush(formatter)
+	for _, sink := range sinks {
+		flush(sink.Formatter)
+	}
+	return firstErr
+}
@@ -0,0 +1,77 @@
+package logrus
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushWriter struct {
+	bytes.Buffer
+	flushed int
+	err     error
+}
+
+func (w *flushWriter) Flush() error {
+	w.flushed++
+	return w.err
+}
+
+type flushHook struct {
+	flushed int
+}
+
+func (h *flushHook) Levels() []Level   { return AllLevels }
+func (h *flushHook) Fire(*Entry) error { return nil }
+func (h *flushHook) Flush() error {
+	h.flushed++
+	return nil
+}
+
+type flushFormatter struct {
+	JSONFormatter
+	flushed int
+}
+
+func (f *flushFormatter) Flush() error {
+	f.flushed++
+	return nil
+}
+
+func TestLoggerFlush(t *testing.T) {
+	out := &flushWriter{}
+	hook := &flushHook{}
+	formatter := &flushFormatter{}
+	logger := New()
+	logger.Out = out
+	logger.Formatter = formatter
+	logger.Hooks.Add(hook)
+
+	assert.NoError(t, logger.Flush())
+	assert.Equal(t, 1, out.flushed)
+	assert.Equal(t, 1, hook.flushed, "hooks registered for several levels are flushed once")
+	assert.Equal(t, 1, formatter.flushed)
+}
+
+func TestLoggerFlushReturnsError(t *testing.T) {
+	out := &flushWriter{err: errors.New("disk full")}
+	hook := &flushHook{}
+	logger := New()
+	logger.Out = out
+	logger.Hooks.Add(hook)
+
+	assert.EqualError(t, logger.Flush(), "disk full")
+	assert.Equal(t, 1, hook.flushed, "hooks are flushed even if the output fails")
+}
+
+func TestStandardLoggerFlushedOnExit(t *testing.T) {
+	out := &flushWriter{}
+	old := std.Out
+	defer func() { std.Out = old }()
+	std.Out = out
+
+	runHandlers()
+	assert.Equal(t, 1, out.flushed)
+}
@@ -0,0 +1,62 @@
+package logrus
+
+import (
+	"fmt"
+	"os"
+)
+
+// Defines the keys of the meta entry written when EmitFormatMeta is set.
+var FormatMetaKey = "log_meta"
+var SchemaVersionKey = "schema_version"
+
+// formatMetaFields describes the formatter and the field names of the logger.
+func (logger *Logger) formatMetaFields() Fields {
+	fieldMap := map[string]string{
+		FieldKeyTime:  FieldKeyTime,
+		FieldKeyLevel: FieldKeyLevel,
+		FieldKeyMsg:   FieldKeyMsg,
+		"module":      ModuleNameKey,
+		"error":       ErrorKey,
+		"stacktrace":  StacktraceKey,
+	}
+	if f, ok := logger.Formatter.(*JSONFormatter); ok {
+		for _, key := range []fieldKey{FieldKeyTime, FieldKeyLevel, FieldKeyMsg} {
+			fieldMap[string(key)] = f.FieldMap.resolve(key)
+		}
+	}
+	fields := Fields{
+		FormatMetaKey: true,
+		"formatter":   fmt.Sprintf("%T", logger.Formatter),
+		"field_map":   fieldMap,
+	}
+	if logger.SchemaVersion != "" {
+		fields[SchemaVersionKey] = logger.SchemaVersion
+	}
+	return fields
+}
+
+// writeFormatMeta writes the meta entry describing the format, it is called
+// once before the first entry when EmitFormatMeta is set. The meta entry is
+// written whatever the level and doesn't fire the hooks.
+func (logger *Logger) writeFormatMeta() {
+	meta := NewEntry(logger)
+	meta.Data = logger.formatMetaFields()
+	meta.Time = logger.now()
+	meta.Level = InfoLevel
+	meta.Message = "log format"
+	serialized, err := logger.Formatter.Format(meta)
+	if err != nil {
+		logger.handleError("Failed to obtain reader, %v\n", err)
+		return
+	}
+	logger.mu.Lock()
+	out := logger.Out
+	if out == nil {
+		out = os.Stderr
+	}
+	_, err = out.Write(serialized)
+	logger.mu.Unlock()
+	if err != nil {
+		logger.handleError("Failed to write to log, %v\n", err)
+	}
+}
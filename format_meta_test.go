@@ -0,0 +1,53 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmitFormatMeta(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &JSONFormatter{FieldMap: FieldMap{FieldKeyMsg: "message"}}
+	logger.EmitFormatMeta = true
+	logger.SchemaVersion = "2"
+
+	logger.Info("first")
+	logger.Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if assert.Len(t, lines, 3, "the meta entry should only be written once") {
+		var meta Fields
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &meta))
+		assert.Equal(t, true, meta["log_meta"])
+		assert.Equal(t, "2", meta["schema_version"])
+		assert.Equal(t, "*logrus.JSONFormatter", meta["formatter"])
+		assert.Equal(t, map[string]interface{}{
+			"time":       "time",
+			"level":      "level",
+			"msg":        "message",
+			"module":     "module",
+			"error":      "error",
+			"stacktrace": "stacktrace",
+		}, meta["field_map"])
+
+		var first Fields
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &first))
+		assert.Equal(t, "first", first["message"])
+	}
+}
+
+func TestEmitFormatMetaDisabled(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Info("test")
+	}, func(fields Fields) {
+		_, ok := fields["log_meta"]
+		assert.False(t, ok)
+		assert.Equal(t, "test", fields["msg"])
+	})
+}
@@ -1,8 +1,13 @@
 package logrus
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
-const DefaultTimestampFormat = time.RFC3339
+// DefaultTimestampFormat is the layout of the timestamps of the formatters
+// without a TimestampFormat.
+const DefaultTimestampFormat = time.RFC3339Nano
 
 // The Formatter interface is used to implement a custom Formatter. It takes an
 // `Entry`. It exposes all the fields, including the default ones:
@@ -43,3 +48,24 @@ func prefixFieldClashes(data Fields) {
 		data["fields.level"] = l
 	}
 }
+
+// sortedKeys returns the keys of data, starting with the ones listed in order
+// and then the others alphabetically.
+func sortedKeys(data Fields, order []string) []string {
+	keys := make([]string, 0, len(data))
+	pinned := make(map[string]bool, len(order))
+	for _, k := range order {
+		if _, ok := data[k]; ok && !pinned[k] {
+			keys = append(keys, k)
+			pinned[k] = true
+		}
+	}
+	rest := len(keys)
+	for k := range data {
+		if !pinned[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys[rest:])
+	return keys
+}
@@ -0,0 +1,27 @@
+package logrus
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID returns the id of the calling goroutine. The runtime doesn't
+// expose it, so it is parsed from the header line of the current stack, which
+// looks like "goroutine 42 [running]:". This is not free, callers should only
+// use it when ReportGoroutineID is enabled.
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	b := bytes.TrimPrefix(buf[:n], goroutinePrefix)
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
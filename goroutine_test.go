@@ -0,0 +1,76 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutineID(t *testing.T) {
+	id := goroutineID()
+	assert.NotEqual(t, uint64(0), id)
+	assert.Equal(t, id, goroutineID(), "id should be stable within a goroutine")
+
+	ch := make(chan uint64)
+	go func() { ch <- goroutineID() }()
+	assert.NotEqual(t, id, <-ch)
+}
+
+func TestGoroutineIDNotReportedByDefault(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Info("test")
+	}, func(fields Fields) {
+		_, ok := fields[GoroutineIDKey]
+		assert.False(t, ok)
+	})
+}
+
+func TestReportGoroutineIDConcurrently(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = new(JSONFormatter)
+	logger.SetReportGoroutineID(true)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			logger.Info("test")
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	ids := map[interface{}]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var fields Fields
+		assert.NoError(t, json.Unmarshal([]byte(line), &fields))
+		assert.NotNil(t, fields[GoroutineIDKey])
+		ids[fields[GoroutineIDKey]] = true
+	}
+	assert.Equal(t, 2, len(ids), "each goroutine should report a distinct id")
+}
+
+func TestGoroutineIDKeyCanBeOverridden(t *testing.T) {
+	defer func() {
+		GoroutineIDKey = "goroutine"
+	}()
+	GoroutineIDKey = "goid"
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetReportGoroutineID(true)
+		log.Info("test")
+	}, func(fields Fields) {
+		assert.NotNil(t, fields["goid"])
+		_, ok := fields["goroutine"]
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,63 @@
+package logrus
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"sync"
+)
+
+var errGzipWriterClosed = errors.New("logrus: write to closed GzipWriter")
+
+// GzipWriter is an io.WriteCloser compressing the log entries on the fly. Set
+// it as `Logger.Out`:
+//
+//    logger.Out = logrus.NewGzipWriter(file)
+//
+// Flush, which is called by Logger.Flush, makes the entries written so far
+// readable by a decompressor. The gzip stream is only complete once the
+// writer is closed, a writer created with NewGzipWriter is closed by the exit
+// handlers so it is valid after Fatal or logrus.Exit. Logger.Close closes it.
+type GzipWriter struct {
+	mu     sync.Mutex
+	gz     *gzip.Writer
+	closed bool
+}
+
+// NewGzipWriter creates a GzipWriter writing the compressed entries to out.
+func NewGzipWriter(out io.Writer) *GzipWriter {
+	w := &GzipWriter{gz: gzip.NewWriter(out)}
+	registerExitWriter(w, func() { w.Close() })
+	return w
+}
+
+func (w *GzipWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errGzipWriterClosed
+	}
+	return w.gz.Write(p)
+}
+
+// Flush writes the pending compressed data to the underlying writer.
+func (w *GzipWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	return w.gz.Flush()
+}
+
+// Close completes the gzip stream. It does not close the underlying writer.
+func (w *GzipWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	unregisterExitWriter(w)
+	return w.gz.Close()
+}
@@ -0,0 +1,68 @@
+package logrus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newGzipTestLogger(out io.Writer) (*Logger, *GzipWriter) {
+	w := NewGzipWriter(out)
+	logger := New()
+	logger.Out = w
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	return logger, w
+}
+
+func TestGzipWriter(t *testing.T) {
+	var compressed bytes.Buffer
+	logger, w := newGzipTestLogger(&compressed)
+	for i := 0; i < 100; i++ {
+		logger.WithField("i", i).Info("test")
+	}
+	assert.NoError(t, w.Close())
+
+	var expected bytes.Buffer
+	for i := 0; i < 100; i++ {
+		b, _ := logger.Formatter.Format(&Entry{Level: InfoLevel, Message: "test", Data: Fields{"i": i}})
+		expected.Write(b)
+	}
+	r, err := gzip.NewReader(&compressed)
+	if assert.NoError(t, err) {
+		lines, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.String(), string(lines))
+	}
+
+	_, err = w.Write([]byte("late"))
+	assert.Error(t, err, "writes after Close should fail")
+	assert.NoError(t, w.Close())
+}
+
+func TestGzipWriterFlushedByLogger(t *testing.T) {
+	var compressed bytes.Buffer
+	logger, w := newGzipTestLogger(&compressed)
+	defer w.Close()
+	logger.Info("flushed")
+	assert.NoError(t, logger.Flush())
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed.Bytes()))
+	if assert.NoError(t, err) {
+		// the stream isn't closed yet, everything flushed is readable
+		lines, err := ioutil.ReadAll(r)
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+		assert.Equal(t, "level=info msg=flushed \n", string(lines))
+	}
+}
+
+func TestGzipWriterUnregisteredOnClose(t *testing.T) {
+	current := len(exitWriters)
+	w := NewGzipWriter(ioutil.Discard)
+	assert.Len(t, exitWriters, current+1)
+	assert.NoError(t, w.Close())
+	assert.Len(t, exitWriters, current, "a closed writer should not be kept by the exit handlers")
+}
@@ -1,6 +1,8 @@
 package logrus
 
 import (
+	"bytes"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -122,3 +124,205 @@ func TestErrorHookShouldFireOnError(t *testing.T) {
 		assert.Equal(t, hook.Fired, true)
 	})
 }
+
+func TestLevelHook(t *testing.T) {
+	hook := new(TestHook)
+	levelHook := NewLevelHook(hook, ErrorLevel, FatalLevel)
+	assert.Equal(t, []Level{ErrorLevel, FatalLevel}, levelHook.Levels())
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Hooks.Add(levelHook)
+		log.Info("test")
+	}, func(fields Fields) {
+		assert.False(t, hook.Fired, "the hook should not fire for info entries")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Hooks.Add(levelHook)
+		log.Error("test")
+	}, func(fields Fields) {
+		assert.True(t, hook.Fired)
+	})
+}
+
+type errorOnlyHook struct {
+	TestHook
+}
+
+func (hook *errorOnlyHook) Levels() []Level {
+	return []Level{ErrorLevel}
+}
+
+func TestLevelHookKeepsSupportedLevels(t *testing.T) {
+	levelHook := NewLevelHook(new(errorOnlyHook), InfoLevel, ErrorLevel)
+	assert.Equal(t, []Level{ErrorLevel}, levelHook.Levels(), "levels the hook doesn't support should be ignored")
+}
+
+func TestLevelHookMutates(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Hooks.Add(NewLevelHook(NewMutatingHook(func(entry *Entry) {
+			entry.Data["enriched"] = true
+		}), InfoLevel))
+		log.Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, true, fields["enriched"])
+	})
+}
+
+func TestLevelHookKeepsPriority(t *testing.T) {
+	var order []string
+	record := func(name string) Hook {
+		return &hookFunc{func(*Entry) { order = append(order, name) }}
+	}
+
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	logger.Hooks.Add(record("ship"))
+	logger.Hooks.Add(NewLevelHook(NewPriorityHook(record("enrich"), 10), InfoLevel))
+	logger.Info("test")
+
+	assert.Equal(t, []string{"enrich", "ship"}, order)
+}
+
+func TestLevelHookFlushes(t *testing.T) {
+	hook := &flushHook{}
+	logger := New()
+	logger.Hooks.Add(NewLevelHook(hook, ErrorLevel))
+
+	assert.NoError(t, logger.Flush())
+	assert.Equal(t, 1, hook.flushed)
+}
+
+func TestLevelHookCloses(t *testing.T) {
+	hook := &closeHook{}
+	logger := New()
+	logger.Hooks.Add(NewLevelHook(hook, ErrorLevel))
+
+	assert.NoError(t, logger.Close())
+	assert.Equal(t, 1, hook.closed)
+}
+
+func TestListAndReplaceHooks(t *testing.T) {
+	logger := New()
+	all := new(TestHook)
+	errorOnly := new(errorOnlyHook)
+	logger.Hooks.Add(all)
+	logger.Hooks.Add(errorOnly)
+	assert.Equal(t, []Hook{all, errorOnly}, logger.ListHooks())
+
+	replacement := new(TestHook)
+	hooks := make(LevelHooks)
+	hooks.Add(replacement)
+	old := logger.ReplaceHooks(hooks)
+	assert.Equal(t, []Hook{replacement}, logger.ListHooks())
+
+	logger.Out = &bytes.Buffer{}
+	logger.Error("test")
+	assert.True(t, replacement.Fired)
+	assert.False(t, all.Fired, "replaced hooks should not fire")
+
+	logger.ReplaceHooks(old)
+	assert.Equal(t, []Hook{all, errorOnly}, logger.ListHooks())
+	logger.ReplaceHooks(nil)
+	assert.Empty(t, logger.ListHooks())
+}
+
+func TestReplaceHooksWhileLogging(t *testing.T) {
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			logger.Info("test")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		hooks := make(LevelHooks)
+		hooks.Add(&hookFunc{func(*Entry) {}})
+		logger.ReplaceHooks(hooks)
+	}
+	wg.Wait()
+}
+
+func TestMutatingHook(t *testing.T) {
+	var seen interface{}
+	LogAndAssertJSON(t, func(log *Logger) {
+		// added first, the mutations are still visible to it
+		log.Hooks.Add(&hookFunc{func(entry *Entry) { seen = entry.Data["trace"] }})
+		log.Hooks.Add(NewMutatingHook(func(entry *Entry) {
+			entry.Data["trace"] = "abc"
+			delete(entry.Data, "internal")
+		}))
+		log.Hooks.Add(NewMutatingHook(func(entry *Entry) {
+			entry.Data["trace"] = entry.Data["trace"].(string) + "-def"
+		}, InfoLevel))
+		log.WithFields(Fields{"internal": true, "a": 1}).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "abc-def", fields["trace"], "mutating hooks should run in registration order")
+		assert.NotContains(t, fields, "internal")
+		assert.Equal(t, float64(1), fields["a"])
+		assert.Equal(t, "abc-def", seen)
+	})
+}
+
+func TestHookPriority(t *testing.T) {
+	var order []string
+	record := func(name string) Hook {
+		return &hookFunc{func(*Entry) { order = append(order, name) }}
+	}
+
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	logger.Hooks.Add(record("ship"))
+	logger.Hooks.Add(NewPriorityHook(record("metrics"), -1))
+	logger.Hooks.Add(NewPriorityHook(record("enrich"), 10))
+	logger.Hooks.Add(record("ship2"))
+	logger.Hooks.Add(NewPriorityHook(record("enrich2"), 10))
+	logger.Info("test")
+
+	assert.Equal(t, []string{"enrich", "enrich2", "ship", "ship2", "metrics"}, order)
+}
+
+func TestPriorityHookMutates(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Hooks.Add(NewPriorityHook(NewMutatingHook(func(entry *Entry) {
+			entry.Data["enriched"] = true
+		}), 1))
+		log.Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, true, fields["enriched"])
+	})
+}
+
+func TestPriorityHookFlushes(t *testing.T) {
+	hook := &flushHook{}
+	logger := New()
+	logger.Hooks.Add(NewPriorityHook(hook, 1))
+
+	assert.NoError(t, logger.Flush())
+	assert.Equal(t, 1, hook.flushed)
+}
+
+func TestHookCanKeepEntry(t *testing.T) {
+	kept := make(chan *Entry, 1)
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	// the text formatter renames the fields clashing with the default keys
+	logger.Formatter = &TextFormatter{DisableColors: true}
+	logger.Hooks.Add(&hookFunc{func(entry *Entry) { kept <- entry }})
+
+	logger.WithFields(Fields{"msg": "clash", "a": 1}).Info("test")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		entry := <-kept
+		assert.Equal(t, Fields{"msg": "clash", "a": 1}, entry.Data)
+		assert.Equal(t, "test", entry.Message)
+	}()
+	logger.WithField("a", 2).Info("other")
+	<-done
+}
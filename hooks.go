@@ -1,10 +1,25 @@
 package logrus
 
+import (
+	"io"
+	"reflect"
+	"sort"
+)
+
 // A hook to be fired when logging on the logging levels returned from
 // `Levels()` on your implementation of the interface. Note that this is not
 // fired in a goroutine or a channel with workers, you should handle such
 // functionality yourself if your call is non-blocking and you don't wish for
 // the logging calls for levels returned from `Levels()` to block.
+//
+// A hook may modify the entry, the changes are seen by the hooks fired after
+// it and written. Once all the hooks fired the entry is not modified anymore,
+// the logger formats a copy, so a hook may keep it, e.g. to send it from a
+// goroutine.
+//
+// Hooks should not log through the logger firing them. Such entries are
+// detected and written to stderr, without firing the hooks, instead of
+// recursing or deadlocking.
 type Hook interface {
 	Levels() []Level
 	Fire(*Entry) error
@@ -15,16 +30,197 @@ type LevelHooks map[Level][]Hook
 
 // Add a hook to an instance of logger. This is called with
 // `log.Hooks.Add(new(MyHook))` where `MyHook` implements the `Hook` interface.
+// The hooks are kept ordered by priority, see PrioritizedHook.
 func (hooks LevelHooks) Add(hook Hook) {
+	priority := hookPriority(hook)
 	for _, level := range hook.Levels() {
-		hooks[level] = append(hooks[level], hook)
+		old := hooks[level]
+		// after the hooks of the same priority, so ties keep the order of Add
+		i := sort.Search(len(old), func(i int) bool { return hookPriority(old[i]) < priority })
+		// a new slice, the one being fired by emit must not change
+		list := make([]Hook, 0, len(old)+1)
+		list = append(list, old[:i]...)
+		list = append(list, hook)
+		list = append(list, old[i:]...)
+		hooks[level] = list
+	}
+}
+
+// PrioritizedHook is a hook fired before the hooks of a lower priority, e.g.
+// an enrichment hook adding fields before the hook shipping the entries. The
+// hooks without a Priority have priority 0, hooks of the same priority are
+// fired in the order they were added. Wrap a hook with NewPriorityHook to set
+// its priority.
+type PrioritizedHook interface {
+	Hook
+	Priority() int
+}
+
+func hookPriority(hook Hook) int {
+	if p, ok := hook.(PrioritizedHook); ok {
+		return p.Priority()
+	}
+	return 0
+}
+
+// PriorityHook sets the priority of a hook, see PrioritizedHook:
+//
+//    log.Hooks.Add(logrus.NewPriorityHook(enrichHook, 10))
+//
+// The wrapped hook still mutates the entries if it is a MutatingHook, and is
+// flushed and closed by Logger.Flush and Logger.Close if it is a Flusher and
+// an io.Closer.
+type PriorityHook struct {
+	Hook     Hook
+	priority int
+}
+
+// NewPriorityHook creates a hook firing hook with the given priority.
+func NewPriorityHook(hook Hook, priority int) *PriorityHook {
+	return &PriorityHook{Hook: hook, priority: priority}
+}
+
+func (hook *PriorityHook) Levels() []Level {
+	return hook.Hook.Levels()
+}
+
+func (hook *PriorityHook) Fire(entry *Entry) error {
+	return hook.Hook.Fire(entry)
+}
+
+func (hook *PriorityHook) Priority() int {
+	return hook.priority
+}
+
+func (hook *PriorityHook) Mutate(entry *Entry) {
+	if m, ok := hook.Hook.(MutatingHook); ok {
+		m.Mutate(entry)
 	}
 }
 
+func (hook *PriorityHook) Flush() error {
+	if f, ok := hook.Hook.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (hook *PriorityHook) Close() error {
+	if c, ok := hook.Hook.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// LevelHook restricts a hook to some of its levels, e.g. to only send the
+// errors to a hook firing for all the levels:
+//
+//    log.Hooks.Add(logrus.NewLevelHook(hook, logrus.ErrorLevel, logrus.FatalLevel))
+//
+// As hooks are only fired for the levels they return, the other entries don't
+// cost anything to the wrapped hook. The wrapped hook still mutates the
+// entries if it is a MutatingHook, keeps its priority if it is a
+// PrioritizedHook, and is flushed and closed by Logger.Flush and Logger.Close
+// if it is a Flusher and an io.Closer.
+type LevelHook struct {
+	Hook   Hook
+	levels []Level
+}
+
+// NewLevelHook creates a hook firing hook for the given levels it supports.
+func NewLevelHook(hook Hook, levels ...Level) *LevelHook {
+	supported := make(map[Level]bool)
+	for _, level := range hook.Levels() {
+		supported[level] = true
+	}
+	h := &LevelHook{Hook: hook}
+	for _, level := range levels {
+		if supported[level] {
+			h.levels = append(h.levels, level)
+		}
+	}
+	return h
+}
+
+func (hook *LevelHook) Levels() []Level {
+	return hook.levels
+}
+
+func (hook *LevelHook) Fire(entry *Entry) error {
+	return hook.Hook.Fire(entry)
+}
+
+func (hook *LevelHook) Priority() int {
+	return hookPriority(hook.Hook)
+}
+
+func (hook *LevelHook) Mutate(entry *Entry) {
+	if m, ok := hook.Hook.(MutatingHook); ok {
+		m.Mutate(entry)
+	}
+}
+
+func (hook *LevelHook) Flush() error {
+	if f, ok := hook.Hook.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (hook *LevelHook) Close() error {
+	if c, ok := hook.Hook.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// MutatingHook is a hook transforming the entries before they are formatted,
+// e.g. adding a computed field or removing internal ones. The entry's Data is
+// a copy the hook may freely modify. Mutate is called, for the levels of the
+// hook, before the Fire of every hook, so all the hooks see the transformed
+// entry. The mutating hooks run in the order of the hooks, see
+// PrioritizedHook, then the hooks are fired in the same order.
+type MutatingHook interface {
+	Hook
+	Mutate(*Entry)
+}
+
+type mutateFunc struct {
+	mutate func(*Entry)
+	levels []Level
+}
+
+// NewMutatingHook creates a MutatingHook calling mutate for the entries of the
+// given levels, all the levels if none is given:
+//
+//    log.Hooks.Add(logrus.NewMutatingHook(func(entry *logrus.Entry) {
+//      delete(entry.Data, "internal")
+//    }))
+func NewMutatingHook(mutate func(*Entry), levels ...Level) MutatingHook {
+	if len(levels) == 0 {
+		levels = AllLevels
+	}
+	return &mutateFunc{mutate: mutate, levels: levels}
+}
+
+func (hook *mutateFunc) Levels() []Level   { return hook.levels }
+func (hook *mutateFunc) Mutate(e *Entry)   { hook.mutate(e) }
+func (hook *mutateFunc) Fire(*Entry) error { return nil }
+
 // Fire all the hooks for the passed level. Used by `entry.log` to fire
-// appropriate hooks for a log entry.
+// appropriate hooks for a log entry. The mutating hooks transform the entry
+// first, see MutatingHook.
 func (hooks LevelHooks) Fire(level Level, entry *Entry) error {
-	for _, hook := range hooks[level] {
+	return fireHooks(hooks[level], entry)
+}
+
+func fireHooks(hooks []Hook, entry *Entry) error {
+	for _, hook := range hooks {
+		if m, ok := hook.(MutatingHook); ok {
+			m.Mutate(entry)
+		}
+	}
+	for _, hook := range hooks {
 		if err := hook.Fire(entry); err != nil {
 			return err
 		}
@@ -32,3 +228,45 @@ func (hooks LevelHooks) Fire(level Level, entry *Entry) error {
 
 	return nil
 }
+
+// ListHooks returns the hooks of the logger, each hook once even when it is
+// registered for several levels, e.g. for a diagnostics endpoint.
+func (logger *Logger) ListHooks() []Hook {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	return logger.uniqueHooks()
+}
+
+// ReplaceHooks replaces all the hooks of the logger and returns the previous
+// ones, e.g. to restore them at the end of a test. Passing nil removes all the
+// hooks. It is safe to call while logging, each entry fires either the
+// previous or the new hooks.
+func (logger *Logger) ReplaceHooks(hooks LevelHooks) LevelHooks {
+	if hooks == nil {
+		hooks = make(LevelHooks)
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	old := logger.Hooks
+	logger.Hooks = hooks
+	return old
+}
+
+// uniqueHooks returns the hooks of the logger from the most to the least
+// severe level, a hook registered for several levels is only returned once.
+func (logger *Logger) uniqueHooks() []Hook {
+	var unique []Hook
+	seen := make(map[Hook]bool)
+	for _, level := range AllLevels {
+		for _, hook := range logger.Hooks[level] {
+			if reflect.TypeOf(hook).Comparable() {
+				if seen[hook] {
+					continue
+				}
+				seen[hook] = true
+			}
+			unique = append(unique, hook)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,81 @@
+package logrus_counter
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CounterHook counts the logged entries per level and module, e.g. to export
+// the log volume as metrics. Counting an entry of a level and module seen
+// before only takes a read lock and an atomic increment.
+type CounterHook struct {
+	mu     sync.RWMutex
+	counts map[string]*uint64
+}
+
+// Creates a hook to be added to an instance of logger. This is called with
+// `log.Hooks.Add(NewCounterHook())`.
+func NewCounterHook() *CounterHook {
+	return &CounterHook{counts: make(map[string]*uint64)}
+}
+
+// Key returns the key of the count of a level and module in Counts, the level
+// name for the default module, e.g. `error`, and the level name and the module
+// separated by a slash otherwise, e.g. `error/db`.
+func Key(level logrus.Level, module string) string {
+	if module == logrus.DefaultModuleName {
+		return level.String()
+	}
+	return level.String() + "/" + module
+}
+
+func (hook *CounterHook) Fire(entry *logrus.Entry) error {
+	key := Key(entry.Level, entry.ModuleName())
+
+	// incremented with the lock held, so a count is never added to the map
+	// Reset just dropped
+	hook.mu.RLock()
+	count, ok := hook.counts[key]
+	if ok {
+		atomic.AddUint64(count, 1)
+	}
+	hook.mu.RUnlock()
+	if ok {
+		return nil
+	}
+
+	hook.mu.Lock()
+	if count, ok = hook.counts[key]; !ok {
+		count = new(uint64)
+		hook.counts[key] = count
+	}
+	atomic.AddUint64(count, 1)
+	hook.mu.Unlock()
+	return nil
+}
+
+func (hook *CounterHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Counts returns a snapshot of the counts, see Key for their keys. Levels and
+// modules without entries are absent.
+func (hook *CounterHook) Counts() map[string]uint64 {
+	hook.mu.RLock()
+	defer hook.mu.RUnlock()
+	counts := make(map[string]uint64, len(hook.counts))
+	for key, count := range hook.counts {
+		counts[key] = atomic.LoadUint64(count)
+	}
+	return counts
+}
+
+// Reset sets all the counts back to zero. An entry counted concurrently is
+// either dropped with the others or counted after the reset.
+func (hook *CounterHook) Reset() {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	hook.counts = make(map[string]*uint64)
+}
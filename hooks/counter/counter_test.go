@@ -0,0 +1,105 @@
+package logrus_counter
+
+import (
+	"io/ioutil"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCounterHook(t *testing.T) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook := NewCounterHook()
+	log.Hooks.Add(hook)
+
+	db := log.NewModule("db")
+	log.Info("started")
+	log.Info("listening")
+	log.Error("failed")
+	db.Error("query failed")
+	db.Warn("slow query")
+	db.Debug("not logged")
+
+	expected := map[string]uint64{
+		"info":       2,
+		"error":      1,
+		"error/db":   1,
+		"warning/db": 1,
+	}
+	if counts := hook.Counts(); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("unexpected counts %v", counts)
+	}
+
+	hook.Reset()
+	if counts := hook.Counts(); len(counts) != 0 {
+		t.Errorf("counts should be empty after Reset, got %v", counts)
+	}
+}
+
+func TestCounterHookConcurrent(t *testing.T) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook := NewCounterHook()
+	log.Hooks.Add(hook)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				log.Info("test")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count := hook.Counts()[Key(logrus.InfoLevel, logrus.DefaultModuleName)]; count != 800 {
+		t.Errorf("expected 800 info entries, got %d", count)
+	}
+}
+
+func TestCounterHookNonStringModule(t *testing.T) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook := NewCounterHook()
+	log.Hooks.Add(hook)
+
+	log.WithField(logrus.ModuleNameKey, 42).Info("numbered")
+	if count := hook.Counts()["info/42"]; count != 1 {
+		t.Errorf("expected 1 info/42 entry, got %v", hook.Counts())
+	}
+}
+
+func TestCounterHookResetWhileCounting(t *testing.T) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook := NewCounterHook()
+	log.Hooks.Add(hook)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				log.Info("test")
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		hook.Reset()
+	}
+	wg.Wait()
+
+	hook.Reset()
+	for i := 0; i < 5; i++ {
+		log.Info("test")
+	}
+	if count := hook.Counts()["info"]; count != 5 {
+		t.Errorf("expected 5 info entries after Reset, got %d", count)
+	}
+}
@@ -0,0 +1,122 @@
+package logrus_histogram
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultKey is the field holding the duration, in milliseconds, when no key
+// is given to NewHistogramHook.
+const DefaultKey = "duration_ms"
+
+// DefaultBuckets are the upper bounds, in milliseconds, used when no buckets
+// are given to NewHistogramHook.
+var DefaultBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// HistogramHook feeds the duration field of the logged entries into an
+// in-memory histogram per module, which gives latency distributions of e.g.
+// the logged request completions without separate instrumentation. Entries
+// without the field, or with a non-numeric value, are ignored. A
+// time.Duration value is converted to milliseconds.
+type HistogramHook struct {
+	Key     string
+	Buckets []float64
+
+	mu         sync.Mutex
+	histograms map[string]*Histogram
+}
+
+// Histogram is a snapshot of the durations logged for a module. Counts[i] is
+// the number of durations lower or equal to Buckets[i] and greater than the
+// previous bucket, the last count is for the durations greater than all the
+// buckets.
+type Histogram struct {
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+// Creates a hook to be added to an instance of logger. This is called with
+// `log.Hooks.Add(NewHistogramHook("duration_ms", nil))`, the buckets are the
+// sorted upper bounds of the histogram, DefaultBuckets when nil.
+func NewHistogramHook(key string, buckets []float64) *HistogramHook {
+	if key == "" {
+		key = DefaultKey
+	}
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramHook{
+		Key:        key,
+		Buckets:    buckets,
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+func (hook *HistogramHook) Fire(entry *logrus.Entry) error {
+	value, ok := milliseconds(entry.Data[hook.Key])
+	if !ok {
+		return nil
+	}
+	module := entry.ModuleName()
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	h, ok := hook.histograms[module]
+	if !ok {
+		h = &Histogram{Buckets: hook.Buckets, Counts: make([]uint64, len(hook.Buckets)+1)}
+		hook.histograms[module] = h
+	}
+	h.Counts[sort.SearchFloat64s(h.Buckets, value)]++
+	h.Count++
+	h.Sum += value
+	return nil
+}
+
+func (hook *HistogramHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Histogram returns a snapshot of the histogram of a module, with no counts
+// when nothing was logged for it.
+func (hook *HistogramHook) Histogram(module string) Histogram {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	h, ok := hook.histograms[module]
+	if !ok {
+		return Histogram{Buckets: hook.Buckets, Counts: make([]uint64, len(hook.Buckets)+1)}
+	}
+	snapshot := *h
+	snapshot.Counts = append([]uint64(nil), h.Counts...)
+	return snapshot
+}
+
+func milliseconds(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case time.Duration:
+		return float64(v) / float64(time.Millisecond), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,72 @@
+package logrus_histogram
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestHistogramHook(t *testing.T) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook := NewHistogramHook("", []float64{100, 10, 50})
+	log.Hooks.Add(hook)
+
+	db := log.NewModule("db")
+	for _, duration := range []interface{}{3, 10, 11.5, 50, 99, 1000, 20 * time.Millisecond, "slow"} {
+		db.WithField("duration_ms", duration).Info("query")
+	}
+	log.WithField("duration_ms", 5).Info("request")
+	db.Info("no duration")
+
+	h := hook.Histogram("db")
+	if !reflect.DeepEqual(h.Buckets, []float64{10, 50, 100}) {
+		t.Errorf("buckets should be sorted, got %v", h.Buckets)
+	}
+	if !reflect.DeepEqual(h.Counts, []uint64{2, 3, 1, 1}) {
+		t.Errorf("unexpected counts %v", h.Counts)
+	}
+	if h.Count != 7 || h.Sum != 1193.5 {
+		t.Errorf("unexpected count %d or sum %v", h.Count, h.Sum)
+	}
+
+	h = hook.Histogram(logrus.DefaultModuleName)
+	if !reflect.DeepEqual(h.Counts, []uint64{1, 0, 0, 0}) {
+		t.Errorf("unexpected counts %v for the default module", h.Counts)
+	}
+
+	h = hook.Histogram("http")
+	if h.Count != 0 || len(h.Counts) != 4 {
+		t.Errorf("expected an empty histogram, got %v", h)
+	}
+}
+
+func TestHistogramHookKey(t *testing.T) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook := NewHistogramHook("latency", nil)
+	log.Hooks.Add(hook)
+
+	log.WithField("latency", 2*time.Second).Info("request")
+	log.WithField("duration_ms", 2).Info("request")
+
+	h := hook.Histogram(logrus.DefaultModuleName)
+	if h.Count != 1 || h.Counts[9] != 1 {
+		t.Errorf("expected a single duration in the 2500ms bucket, got %v", h.Counts)
+	}
+}
+
+func TestHistogramHookNonStringModule(t *testing.T) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook := NewHistogramHook("", nil)
+	log.Hooks.Add(hook)
+
+	log.WithFields(logrus.Fields{logrus.ModuleNameKey: 42, "duration_ms": 3}).Info("query")
+	if h := hook.Histogram("42"); h.Count != 1 {
+		t.Errorf("expected a duration for module 42, got %v", h)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/syslog"
 	"os"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
@@ -15,14 +16,41 @@ type SyslogHook struct {
 	Writer        *syslog.Writer
 	SyslogNetwork string
 	SyslogRaddr   string
+
+	priority syslog.Priority
+	tag      string
+
+	// one writer per module, syslog tags are set when connecting
+	mu      sync.Mutex
+	writers map[string]*syslog.Writer
 }
 
+// maxModuleWriters is the number of modules connected with their own tag, the
+// entries of the other modules are sent through Writer.
+const maxModuleWriters = 64
+
 // Creates a hook to be added to an instance of logger. This is called with
 // `hook, err := NewSyslogHook("udp", "localhost:514", syslog.LOG_DEBUG, "")`
 // `if err == nil { log.Hooks.Add(hook) }`
+//
+// The severity of the messages is mapped from the level of the entries, the
+// facility is the one of the priority. Entries of a module are tagged with
+// `tag/module`, or just the module name when tag is empty. Each module opens
+// its own connection, so the module names should be few and fixed, the
+// modules past the first 64 are sent with the hook's tag. Errors, e.g. a lost
+// connection, are returned by Fire and reported to the logger's ErrorHandler.
+// Closing the logger closes the connections, see Close.
 func NewSyslogHook(network, raddr string, priority syslog.Priority, tag string) (*SyslogHook, error) {
 	w, err := syslog.Dial(network, raddr, priority, tag)
-	return &SyslogHook{w, network, raddr}, err
+	hook := &SyslogHook{
+		Writer:        w,
+		SyslogNetwork: network,
+		SyslogRaddr:   raddr,
+		priority:      priority,
+		tag:           tag,
+		writers:       make(map[string]*syslog.Writer),
+	}
+	return hook, err
 }
 
 func (hook *SyslogHook) Fire(entry *logrus.Entry) error {
@@ -32,24 +60,89 @@ func (hook *SyslogHook) Fire(entry *logrus.Entry) error {
 		return err
 	}
 
-	switch entry.Level {
-	case logrus.PanicLevel:
-		return hook.Writer.Crit(line)
-	case logrus.FatalLevel:
-		return hook.Writer.Crit(line)
-	case logrus.ErrorLevel:
-		return hook.Writer.Err(line)
-	case logrus.WarnLevel:
-		return hook.Writer.Warning(line)
-	case logrus.InfoLevel:
-		return hook.Writer.Info(line)
-	case logrus.DebugLevel:
-		return hook.Writer.Debug(line)
-	case logrus.TraceLevel:
-		return hook.Writer.Debug(line)
+	w, err := hook.writer(entry.ModuleName())
+	if err != nil {
+		return err
+	}
+
+	switch logrus.SyslogSeverity(entry.Level) {
+	case logrus.SyslogCritical:
+		return w.Crit(line)
+	case logrus.SyslogError:
+		return w.Err(line)
+	case logrus.SyslogWarning:
+		return w.Warning(line)
+	case logrus.SyslogInfo:
+		return w.Info(line)
 	default:
-		return nil
+		return w.Debug(line)
+	}
+}
+
+// writer returns the writer tagged for the module, connecting it on first
+// use.
+func (hook *SyslogHook) writer(module string) (*syslog.Writer, error) {
+	if module == logrus.DefaultModuleName {
+		return hook.Writer, nil
+	}
+
+	hook.mu.Lock()
+	w, ok := hook.writers[module]
+	full := len(hook.writers) >= maxModuleWriters
+	hook.mu.Unlock()
+	if ok {
+		return w, nil
+	}
+	if full {
+		return hook.Writer, nil
+	}
+
+	// dial unlocked, the other modules keep logging while connecting
+	tag := module
+	if hook.tag != "" {
+		tag = hook.tag + "/" + module
+	}
+	w, err := syslog.Dial(hook.SyslogNetwork, hook.SyslogRaddr, hook.priority, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if existing, ok := hook.writers[module]; ok {
+		// connected meanwhile by another entry of the module
+		w.Close()
+		return existing, nil
+	}
+	if len(hook.writers) >= maxModuleWriters {
+		w.Close()
+		return hook.Writer, nil
+	}
+	if hook.writers == nil {
+		hook.writers = make(map[string]*syslog.Writer)
+	}
+	hook.writers[module] = w
+	return w, nil
+}
+
+// Close closes Writer and the writers of the modules, it is called by the
+// logger's Close. It returns the first error.
+func (hook *SyslogHook) Close() error {
+	hook.mu.Lock()
+	writers := hook.writers
+	hook.writers = make(map[string]*syslog.Writer)
+	hook.mu.Unlock()
+
+	var first error
+	if hook.Writer != nil {
+		first = hook.Writer.Close()
+	}
+	for _, w := range writers {
+		if err := w.Close(); err != nil && first == nil {
+			first = err
+		}
 	}
+	return first
 }
 
 func (hook *SyslogHook) Levels() []logrus.Level {
@@ -1,9 +1,14 @@
 package logrus_syslog
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"io/ioutil"
 	"log/syslog"
+	"net"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLocalhostAddAndPrint(t *testing.T) {
@@ -24,3 +29,159 @@ func TestLocalhostAddAndPrint(t *testing.T) {
 
 	log.Info("Congratulations!")
 }
+
+// fakeSyslog returns the address of a UDP server and a channel receiving the
+// messages sent to it.
+func fakeSyslog(t *testing.T) (string, <-chan string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	messages := make(chan string, 16)
+	go func() {
+		defer conn.Close()
+		buf := make([]byte, 4096)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			messages <- string(buf[:n])
+		}
+	}()
+	return conn.LocalAddr().String(), messages
+}
+
+func receive(t *testing.T, messages <-chan string) string {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("No message received")
+	}
+	return ""
+}
+
+func TestSeverityAndModuleTag(t *testing.T) {
+	addr, messages := fakeSyslog(t)
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	log.Level = logrus.TraceLevel
+	hook, err := NewSyslogHook("udp", addr, syslog.LOG_LOCAL0, "app")
+	if err != nil {
+		t.Fatalf("Unable to connect: %v", err)
+	}
+	log.Hooks.Add(hook)
+
+	cases := []struct {
+		log      func()
+		priority syslog.Priority
+		tag      string
+	}{
+		{func() { log.Error("failed") }, syslog.LOG_ERR, "app"},
+		{func() { log.NewModule("db").Warn("slow") }, syslog.LOG_WARNING, "app/db"},
+		{func() { log.NewModule("db").Info("connected") }, syslog.LOG_INFO, "app/db"},
+		{func() { log.NewModule("http").Trace("request") }, syslog.LOG_DEBUG, "app/http"},
+	}
+	for _, c := range cases {
+		c.log()
+		msg := receive(t, messages)
+		prefix := fmt.Sprintf("<%d>", syslog.LOG_LOCAL0|c.priority)
+		if !strings.HasPrefix(msg, prefix) {
+			t.Errorf("Expected priority %s in %q", prefix, msg)
+		}
+		if !strings.Contains(msg, " "+c.tag+"[") {
+			t.Errorf("Expected tag %s in %q", c.tag, msg)
+		}
+	}
+}
+
+func TestConnectionLossReported(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unable to listen: %v", err)
+	}
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	var reported []error
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	log.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	hook, err := NewSyslogHook("tcp", listener.Addr().String(), syslog.LOG_INFO, "app")
+	if err != nil {
+		t.Fatalf("Unable to connect: %v", err)
+	}
+	log.Hooks.Add(hook)
+	listener.Close()
+
+	for i := 0; i < 20 && len(reported) == 0; i++ {
+		log.Info("lost")
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(reported) == 0 {
+		t.Error("The lost connection was not reported to the error handler")
+	}
+}
+
+func TestNonStringModuleTag(t *testing.T) {
+	addr, messages := fakeSyslog(t)
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook, err := NewSyslogHook("udp", addr, syslog.LOG_LOCAL0, "app")
+	if err != nil {
+		t.Fatalf("Unable to connect: %v", err)
+	}
+	log.Hooks.Add(hook)
+
+	log.WithField(logrus.ModuleNameKey, 42).Info("numbered")
+	if msg := receive(t, messages); !strings.Contains(msg, " app/42[") {
+		t.Errorf("Expected tag app/42 in %q", msg)
+	}
+}
+
+func TestModuleWritersBounded(t *testing.T) {
+	addr, messages := fakeSyslog(t)
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook, err := NewSyslogHook("udp", addr, syslog.LOG_LOCAL0, "app")
+	if err != nil {
+		t.Fatalf("Unable to connect: %v", err)
+	}
+	log.Hooks.Add(hook)
+
+	for i := 0; i < maxModuleWriters+1; i++ {
+		log.NewModule(fmt.Sprintf("m%d", i)).Info("hello")
+		receive(t, messages)
+	}
+	if len(hook.writers) != maxModuleWriters {
+		t.Errorf("Expected %d module writers, got %d", maxModuleWriters, len(hook.writers))
+	}
+	log.NewModule("extra").Info("hello")
+	if msg := receive(t, messages); !strings.Contains(msg, " app[") {
+		t.Errorf("Expected tag app past the module writers in %q", msg)
+	}
+}
+
+func TestCloseClosesModuleWriters(t *testing.T) {
+	addr, messages := fakeSyslog(t)
+	log := logrus.New()
+	log.Out = ioutil.Discard
+	hook, err := NewSyslogHook("udp", addr, syslog.LOG_LOCAL0, "app")
+	if err != nil {
+		t.Fatalf("Unable to connect: %v", err)
+	}
+	log.Hooks.Add(hook)
+
+	log.NewModule("db").Info("connected")
+	receive(t, messages)
+	if err := log.Close(); err != nil {
+		t.Errorf("Unexpected error closing the logger: %v", err)
+	}
+	if len(hook.writers) != 0 {
+		t.Errorf("Expected the module writers to be closed, %d left", len(hook.writers))
+	}
+}
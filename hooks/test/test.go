@@ -49,10 +49,19 @@ func NewNullLogger() (*logrus.Logger, *Hook) {
 
 }
 
+// Fire records a copy of the entry. Data is copied as well since later hooks
+// and the logger itself may still modify the entry after it was fired.
 func (t *Hook) Fire(e *logrus.Entry) error {
+	entry := *e
+	entry.Buffer = nil
+	entry.Data = make(logrus.Fields, len(e.Data))
+	for k, v := range e.Data {
+		entry.Data[k] = v
+	}
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	t.Entries = append(t.Entries, e)
+	t.Entries = append(t.Entries, &entry)
 	return nil
 }
 
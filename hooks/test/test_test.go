@@ -37,3 +37,32 @@ func TestAllHooks(t *testing.T) {
 	assert.Equal(1, len(hook.Entries))
 
 }
+
+type modifyHook struct{}
+
+func (h *modifyHook) Fire(e *logrus.Entry) error {
+	e.Data["foo"] = "modified"
+	return nil
+}
+
+func (h *modifyHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func TestEntryFields(t *testing.T) {
+
+	assert := assert.New(t)
+
+	logger, hook := NewNullLogger()
+	logger.Hooks.Add(new(modifyHook))
+
+	logger.WithField("foo", "bar").Info("Hello info")
+	entry := hook.LastEntry()
+	assert.Equal(logrus.InfoLevel, entry.Level)
+	assert.Equal("Hello info", entry.Message)
+	assert.Equal("bar", entry.Data["foo"], "fields changed after firing must not leak into the recorded entry")
+
+	entries := hook.AllEntries()
+	assert.Equal(1, len(entries))
+	assert.Equal("bar", entries[0].Data["foo"])
+}
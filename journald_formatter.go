@@ -0,0 +1,68 @@
+package logrus
+
+import "bytes"
+
+// Syslog severities, see RFC 5424.
+const (
+	SyslogEmergency = 0
+	SyslogAlert     = 1
+	SyslogCritical  = 2
+	SyslogError     = 3
+	SyslogWarning   = 4
+	SyslogNotice    = 5
+	SyslogInfo      = 6
+	SyslogDebug     = 7
+)
+
+// SyslogSeverity returns the syslog severity for a logrus level. Panic and
+// fatal entries are critical, trace entries are debug as syslog has nothing
+// more verbose.
+func SyslogSeverity(level Level) int {
+	switch standardLevel(level) {
+	case PanicLevel, FatalLevel:
+		return SyslogCritical
+	case ErrorLevel:
+		return SyslogError
+	case WarnLevel:
+		return SyslogWarning
+	case InfoLevel:
+		return SyslogInfo
+	default:
+		return SyslogDebug
+	}
+}
+
+// JournaldFormatter prefixes every line formatted by another formatter with
+// the `<N>` priority prefix understood by systemd-journald, so the entries of
+// a service logging to stdout or stderr get the right priority without using
+// the journald socket.
+type JournaldFormatter struct {
+	// Formatter of the lines. Defaults to a TextFormatter without colors and
+	// timestamps, journald adds its own.
+	Formatter Formatter
+}
+
+var defaultJournaldFormatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+
+func (f *JournaldFormatter) Format(entry *Entry) ([]byte, error) {
+	formatter := f.Formatter
+	if formatter == nil {
+		formatter = defaultJournaldFormatter
+	}
+	serialized, err := formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := []byte{'<', byte('0' + SyslogSeverity(entry.Level)), '>'}
+	lines := bytes.SplitAfter(serialized, []byte{'\n'})
+	b := bytes.NewBuffer(make([]byte, 0, len(serialized)+len(lines)*len(prefix)))
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		b.Write(prefix)
+		b.Write(line)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournaldFormatterPrefix(t *testing.T) {
+	expected := map[Level]string{
+		PanicLevel: "<2>",
+		FatalLevel: "<2>",
+		ErrorLevel: "<3>",
+		WarnLevel:  "<4>",
+		InfoLevel:  "<6>",
+		DebugLevel: "<7>",
+		TraceLevel: "<7>",
+	}
+	formatter := &JournaldFormatter{}
+	for level, prefix := range expected {
+		entry := WithField("a", 1)
+		entry.Level = level
+		entry.Message = "test"
+
+		b, err := formatter.Format(entry)
+		assert.NoError(t, err)
+		assert.Equal(t, prefix+"level="+level.String()+" msg=test a=1 \n", string(b))
+	}
+}
+
+func TestJournaldFormatterPrefixesEveryLine(t *testing.T) {
+	formatter := &JournaldFormatter{Formatter: &YAMLBlockFormatter{DisableTimestamp: true}}
+	entry := WithField("a", 1)
+	entry.Level = ErrorLevel
+	entry.Message = "failed"
+
+	b, err := formatter.Format(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "<3>error: failed\n<3>  a: 1\n", string(b))
+}
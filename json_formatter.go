@@ -1,8 +1,10 @@
 package logrus
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
 )
 
 type fieldKey string
@@ -12,8 +14,33 @@ const (
 	FieldKeyMsg   = "msg"
 	FieldKeyLevel = "level"
 	FieldKeyTime  = "time"
+
+	FieldKeySeverity = "severity"
 )
 
+// GCPSeverityMap maps the levels to the severities of Google Cloud Logging.
+var GCPSeverityMap = map[Level]int{
+	PanicLevel: 700, // ALERT
+	FatalLevel: 600, // CRITICAL
+	ErrorLevel: 500, // ERROR
+	WarnLevel:  400, // WARNING
+	InfoLevel:  200, // INFO
+	DebugLevel: 100, // DEBUG
+	TraceLevel: 100, // DEBUG
+}
+
+// SyslogSeverityMap maps the levels to the syslog severities, see
+// SyslogSeverity.
+var SyslogSeverityMap = map[Level]int{
+	PanicLevel: SyslogSeverity(PanicLevel),
+	FatalLevel: SyslogSeverity(FatalLevel),
+	ErrorLevel: SyslogSeverity(ErrorLevel),
+	WarnLevel:  SyslogSeverity(WarnLevel),
+	InfoLevel:  SyslogSeverity(InfoLevel),
+	DebugLevel: SyslogSeverity(DebugLevel),
+	TraceLevel: SyslogSeverity(TraceLevel),
+}
+
 func (f FieldMap) resolve(key fieldKey) string {
 	if k, ok := f[key]; ok {
 		return k
@@ -23,9 +50,14 @@ func (f FieldMap) resolve(key fieldKey) string {
 }
 
 type JSONFormatter struct {
-	// TimestampFormat sets the format used for marshaling timestamps.
+	// TimestampFormat sets the format used for marshaling timestamps,
+	// defaults to DefaultTimestampFormat.
 	TimestampFormat string
 
+	// UseEpoch marshals the timestamps as nanoseconds since the Unix epoch
+	// instead of formatting them.
+	UseEpoch bool
+
 	// DisableTimestamp allows disabling automatic timestamps in output
 	DisableTimestamp bool
 
@@ -39,11 +71,34 @@ type JSONFormatter struct {
 	//    },
 	// }
 	FieldMap FieldMap
+
+	// PrettyPrint indents the JSON objects on several lines, for reading
+	// them. By default every entry is a single line, the newlines of the
+	// message and the fields being escaped, as expected by NDJSON consumers.
+	PrettyPrint bool
+
+	// SeverityMap adds the number of the level of the entries under the
+	// severity key, for the backends expecting numeric severities, e.g.
+	// GCPSeverityMap. Levels missing from the map get no severity.
+	SeverityMap map[Level]int
+
+	// OmitEmpty skips the fields which are nil, empty strings or empty maps,
+	// slices and arrays. false and 0 are kept.
+	OmitEmpty bool
+
+	// DataKey nests the fields of the entries in an object under this key,
+	// e.g. `{"level":"info","msg":"test","fields":{"a":1}}` for "fields", so
+	// they can't clash with the time, level and message keys. The fields are
+	// placed alongside them if it is empty, the default.
+	DataKey string
 }
 
 func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
 	data := make(Fields, len(entry.Data)+3)
 	for k, v := range entry.Data {
+		if f.OmitEmpty && isEmptyValue(v) {
+			continue
+		}
 		switch v := v.(type) {
 		case error:
 			// Otherwise errors are ignored by `encoding/json`
@@ -53,7 +108,24 @@ func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
 			data[k] = v
 		}
 	}
-	prefixFieldClashes(data)
+	order := entry.fieldOrder()
+	if f.DataKey != "" {
+		fields := data
+		data = make(Fields, 5)
+		if len(fields) > 0 || !f.OmitEmpty {
+			if len(order) > 0 {
+				nested, err := marshalOrdered(fields, order)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to marshal fields to JSON, %v", err)
+				}
+				data[f.DataKey] = json.RawMessage(nested)
+			} else {
+				data[f.DataKey] = fields
+			}
+		}
+	} else {
+		prefixFieldClashes(data)
+	}
 
 	timestampFormat := f.TimestampFormat
 	if timestampFormat == "" {
@@ -61,14 +133,73 @@ func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
 	}
 
 	if !f.DisableTimestamp {
-		data[f.FieldMap.resolve(FieldKeyTime)] = entry.Time.Format(timestampFormat)
+		if f.UseEpoch {
+			data[f.FieldMap.resolve(FieldKeyTime)] = entry.Time.UnixNano()
+		} else {
+			data[f.FieldMap.resolve(FieldKeyTime)] = entry.Time.Format(timestampFormat)
+		}
 	}
 	data[f.FieldMap.resolve(FieldKeyMsg)] = entry.Message
 	data[f.FieldMap.resolve(FieldKeyLevel)] = entry.Level.String()
+	if severity, ok := f.SeverityMap[entry.Level]; ok {
+		data[f.FieldMap.resolve(FieldKeySeverity)] = severity
+	} else if severity, ok := f.SeverityMap[standardLevel(entry.Level)]; ok {
+		data[f.FieldMap.resolve(FieldKeySeverity)] = severity
+	}
 
-	serialized, err := json.Marshal(data)
+	var serialized []byte
+	var err error
+	if len(order) > 0 {
+		serialized, err = marshalOrdered(data, order)
+	} else {
+		serialized, err = json.Marshal(data)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Failed to marshal fields to JSON, %v", err)
 	}
+	if f.PrettyPrint {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, serialized, "", "  "); err != nil {
+			return nil, fmt.Errorf("Failed to marshal fields to JSON, %v", err)
+		}
+		serialized = indented.Bytes()
+	}
 	return append(serialized, '\n'), nil
 }
+
+// marshalOrdered marshals data as a JSON object with its keys in the order of
+// sortedKeys, encoding/json always sorts the keys of maps.
+func marshalOrdered(data Fields, order []string) ([]byte, error) {
+	b := &bytes.Buffer{}
+	b.WriteByte('{')
+	for i, k := range sortedKeys(data, order) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		value, err := json.Marshal(data[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// isEmptyValue reports whether a field is skipped by OmitEmpty.
+func isEmptyValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.String, reflect.Map, reflect.Slice, reflect.Array:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		return value.IsNil()
+	}
+	return false
+}
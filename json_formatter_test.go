@@ -1,10 +1,12 @@
 package logrus
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestErrorNotLost(t *testing.T) {
@@ -197,3 +199,183 @@ func TestJSONEnableTimestamp(t *testing.T) {
 		t.Error("Timestamp not present", s)
 	}
 }
+
+func TestJSONFieldOrder(t *testing.T) {
+	logger := New()
+	logger.FieldOrder = []string{"request_id", "level", "msg", "missing"}
+	formatter := &JSONFormatter{DisableTimestamp: true}
+
+	entry := logger.WithFields(Fields{"b": 2, "a": 1, "request_id": "42"})
+	entry.Level = InfoLevel
+	entry.Message = "test"
+	b, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatal("Unable to format entry: ", err)
+	}
+	if s := string(b); s != `{"request_id":"42","level":"info","msg":"test","a":1,"b":2}`+"\n" {
+		t.Errorf("Unexpected field order: %s", s)
+	}
+}
+
+func TestJSONTimestampLayouts(t *testing.T) {
+	entry := WithField("a", 1)
+	entry.Time = time.Date(2017, 8, 22, 10, 11, 12, 345, time.UTC)
+
+	for _, test := range []struct {
+		formatter *JSONFormatter
+		expected  interface{}
+	}{
+		{&JSONFormatter{}, "2017-08-22T10:11:12.000000345Z"},
+		{&JSONFormatter{TimestampFormat: time.RFC3339}, "2017-08-22T10:11:12Z"},
+		{&JSONFormatter{TimestampFormat: "2006/01/02"}, "2017/08/22"},
+		{&JSONFormatter{UseEpoch: true}, json.Number("1503396672000000345")},
+	} {
+		b, err := test.formatter.Format(entry)
+		if err != nil {
+			t.Fatal("Unable to format entry: ", err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		entryData := make(map[string]interface{})
+		if err := decoder.Decode(&entryData); err != nil {
+			t.Fatal("Unable to unmarshal formatted entry: ", err)
+		}
+		if entryData["time"] != test.expected {
+			t.Errorf("Expected time %v, got %v", test.expected, entryData["time"])
+		}
+	}
+}
+
+func TestJSONSeverityMap(t *testing.T) {
+	formatter := &JSONFormatter{DisableTimestamp: true, SeverityMap: GCPSeverityMap}
+	for level, expected := range map[Level]string{
+		ErrorLevel: `"severity":500`,
+		WarnLevel:  `"severity":400`,
+		InfoLevel:  `"severity":200`,
+		PanicLevel: `"severity":700`,
+	} {
+		entry := WithField("a", 1)
+		entry.Level = level
+		b, err := formatter.Format(entry)
+		if err != nil {
+			t.Fatal("Unable to format entry: ", err)
+		}
+		if !strings.Contains(string(b), `"level":"`+level.String()+`"`) || !strings.Contains(string(b), expected) {
+			t.Errorf("Expected the level name and %s in %s", expected, string(b))
+		}
+	}
+
+	formatter = &JSONFormatter{SeverityMap: SyslogSeverityMap, FieldMap: FieldMap{FieldKeySeverity: "priority"}}
+	entry := WithField("a", 1)
+	entry.Level = ErrorLevel
+	b, _ := formatter.Format(entry)
+	if !strings.Contains(string(b), `"priority":3`) {
+		t.Errorf("Expected the syslog severity under the mapped key in %s", string(b))
+	}
+
+	b, _ = (&JSONFormatter{}).Format(entry)
+	if strings.Contains(string(b), FieldKeySeverity) {
+		t.Errorf("No severity expected by default in %s", string(b))
+	}
+}
+
+func TestJSONSingleLine(t *testing.T) {
+	formatter := &JSONFormatter{}
+	logger := New()
+	entry := logger.WithFields(Fields{
+		StacktraceKey: "goroutine 1 [running]:\nmain.main()\n",
+		"raw":         json.RawMessage("{\n\"a\": 1\n}"),
+	})
+	entry.Message = "(string) (len=5) \"multi\"\nline\n"
+
+	for _, order := range [][]string{nil, {"raw"}} {
+		logger.FieldOrder = order
+		b, err := formatter.Format(entry)
+		if err != nil {
+			t.Fatal("Unable to format entry: ", err)
+		}
+		if strings.Count(string(b), "\n") != 1 || !strings.HasSuffix(string(b), "}\n") {
+			t.Errorf("Expected a single line, got %q", string(b))
+		}
+	}
+}
+
+func TestJSONPrettyPrint(t *testing.T) {
+	formatter := &JSONFormatter{DisableTimestamp: true, PrettyPrint: true}
+	entry := WithField("a", 1)
+	entry.Message = "test"
+
+	b, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatal("Unable to format entry: ", err)
+	}
+	expected := "{\n  \"a\": 1,\n  \"level\": \"panic\",\n  \"msg\": \"test\"\n}\n"
+	if string(b) != expected {
+		t.Errorf("Unexpected pretty output %q", string(b))
+	}
+}
+
+func TestJSONOmitEmpty(t *testing.T) {
+	var nilPointer *int
+	fields := Fields{
+		"nil":     nil,
+		"pointer": nilPointer,
+		"empty":   "",
+		"slice":   []string{},
+		"map":     map[string]int{},
+		"false":   false,
+		"zero":    0,
+		"value":   "set",
+	}
+
+	for _, omit := range []bool{false, true} {
+		formatter := &JSONFormatter{DisableTimestamp: true, OmitEmpty: omit}
+		b, err := formatter.Format(WithFields(fields))
+		if err != nil {
+			t.Fatal("Unable to format entry: ", err)
+		}
+		entry := make(map[string]interface{})
+		if err := json.Unmarshal(b, &entry); err != nil {
+			t.Fatal("Unable to unmarshal formatted entry: ", err)
+		}
+
+		for _, key := range []string{"nil", "pointer", "empty", "slice", "map"} {
+			if _, ok := entry[key]; ok == omit {
+				t.Errorf("With OmitEmpty %v, unexpected presence %v of the %s field", omit, ok, key)
+			}
+		}
+		if entry["false"] != false || entry["zero"] != float64(0) || entry["value"] != "set" {
+			t.Errorf("With OmitEmpty %v, false, 0 and the set fields should be kept: %s", omit, b)
+		}
+	}
+}
+
+func TestJSONDataKey(t *testing.T) {
+	entry := WithFields(Fields{"a": 1, "msg": "clash"})
+	entry.Message = "test"
+
+	for _, tc := range []struct {
+		formatter *JSONFormatter
+		expected  string
+	}{
+		{&JSONFormatter{DisableTimestamp: true}, `{"a":1,"fields.msg":"clash","level":"panic","msg":"test"}`},
+		{&JSONFormatter{DisableTimestamp: true, DataKey: "fields"}, `{"fields":{"a":1,"msg":"clash"},"level":"panic","msg":"test"}`},
+	} {
+		b, err := tc.formatter.Format(entry)
+		if err != nil {
+			t.Fatal("Unable to format entry: ", err)
+		}
+		if string(b) != tc.expected+"\n" {
+			t.Errorf("Unexpected layout with DataKey %q: %s", tc.formatter.DataKey, b)
+		}
+	}
+
+	b, _ := (&JSONFormatter{DisableTimestamp: true, DataKey: "fields"}).Format(WithFields(Fields{}))
+	if !strings.Contains(string(b), `"fields":{}`) {
+		t.Errorf("The data key should be kept without fields: %s", b)
+	}
+	b, _ = (&JSONFormatter{DisableTimestamp: true, DataKey: "fields", OmitEmpty: true}).Format(WithFields(Fields{}))
+	if strings.Contains(string(b), `"fields"`) {
+		t.Errorf("The data key should be omitted without fields with OmitEmpty: %s", b)
+	}
+}
@@ -0,0 +1,142 @@
+package logrus
+
+import "github.com/yyscamper/errors"
+
+// SetLevelDefaults sets fields added to every entry logged at the given level
+// or a more severe one, e.g. `SetLevelDefaults(ErrorLevel, Fields{"alert": true})`
+// tags all error, fatal and panic entries. Fields set on the entry itself win
+// over the defaults, and when several thresholds apply the one closest to the
+// entry's level wins. Passing nil removes the defaults of the level.
+func (logger *Logger) SetLevelDefaults(level Level, fields Fields) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	// copy on write, the entries being logged read the map unlocked
+	defaults := make(map[Level]Fields, len(logger.LevelDefaults)+1)
+	for lv, f := range logger.LevelDefaults {
+		defaults[lv] = f
+	}
+	if fields == nil {
+		delete(defaults, level)
+	} else {
+		defaults[level] = fields
+	}
+	logger.LevelDefaults = defaults
+	logger.storeLevelDefaults()
+}
+
+// SetLevelStack enables or disables attaching the current stacktrace, like
+// WithStack does, to every entry logged at the given level or a more severe
+// one. An entry already carrying a stacktrace keeps it.
+func (logger *Logger) SetLevelStack(level Level, enable bool) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	stacks := make(map[Level]bool, len(logger.LevelStacks)+1)
+	for lv := range logger.LevelStacks {
+		stacks[lv] = true
+	}
+	if enable {
+		stacks[level] = true
+	} else {
+		delete(stacks, level)
+	}
+	logger.LevelStacks = stacks
+	logger.storeLevelDefaults()
+}
+
+// levelDefaults is a snapshot of LevelDefaults and LevelStacks, the maps are
+// never modified once stored.
+type levelDefaults struct {
+	fields map[Level]Fields
+	stacks map[Level]bool
+}
+
+// storeLevelDefaults publishes the maps to the entries being logged, it must
+// be called with mu held.
+func (logger *Logger) storeLevelDefaults() {
+	logger.levelDefaults.Store(levelDefaults{logger.LevelDefaults, logger.LevelStacks})
+}
+
+// loadLevelDefaults returns the level defaults without locking the logger,
+// which may be logging from its output. Maps set directly, before logging,
+// are used until a setter is called.
+func (logger *Logger) loadLevelDefaults() levelDefaults {
+	if defaults, ok := logger.levelDefaults.Load().(levelDefaults); ok {
+		return defaults
+	}
+	return levelDefaults{logger.LevelDefaults, logger.LevelStacks}
+}
+
+type levelField struct {
+	level Level
+	key   string
+	value interface{}
+}
+
+// Add a field to the Entry which is only included when the entry is logged at
+// the given level or a more verbose one, e.g. internals only worth logging at
+// DebugLevel. The decision is made when the entry is logged, and an included
+// field replaces a field with the same key set by WithField.
+func (entry *Entry) WithFieldAtLevel(level Level, key string, value interface{}) *Entry {
+	if entry.noop {
+		return entry
+	}
+	// copy, the slice may be shared with the entries derived from this one
+	levelFields := make([]levelField, len(entry.levelFields), len(entry.levelFields)+1)
+	copy(levelFields, entry.levelFields)
+	derived := entry.withData(entry.Data)
+	derived.levelFields = append(levelFields, levelField{level, key, value})
+	return derived
+}
+
+// fieldsAtLevel returns the entry's data with the fields of WithFieldAtLevel
+// which apply to level.
+func (entry *Entry) fieldsAtLevel(level Level) Fields {
+	var data Fields
+	for _, field := range entry.levelFields {
+		if level.severity() < field.level.severity() {
+			continue
+		}
+		if data == nil {
+			data = entry.fieldsWith(nil)
+		}
+		data[field.key] = field.value
+	}
+	if data == nil {
+		return entry.Data
+	}
+	return data
+}
+
+// levelDefaultFields returns the entry's data merged with the level defaults
+// applying to level.
+func (entry *Entry) levelDefaultFields(defaults levelDefaults, level Level) Fields {
+	data := Fields{}
+	// from the least to the most severe threshold, so the closest one wins
+	for i := len(AllLevels) - 1; i >= 0; i-- {
+		if threshold := AllLevels[i]; threshold.enables(level) {
+			for k, v := range defaults.fields[threshold] {
+				data[k] = v
+			}
+		}
+	}
+	_, hasStack := entry.Data[StacktraceKey]
+	if !hasStack && levelStack(defaults.stacks, level) {
+		data[StacktraceKey] = errors.Stack(logrusDepth())
+	}
+	if len(data) == 0 {
+		return entry.Data
+	}
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	return data
+}
+
+func levelStack(stacks map[Level]bool, level Level) bool {
+	for threshold := range stacks {
+		if threshold.enables(level) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package logrus
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelDefaults(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelDefaults(ErrorLevel, Fields{"alert": true})
+		log.Info("test")
+	}, func(fields Fields) {
+		_, ok := fields["alert"]
+		assert.False(t, ok, "info entries should not get the error defaults")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelDefaults(ErrorLevel, Fields{"alert": true})
+		log.Error("test")
+	}, func(fields Fields) {
+		assert.Equal(t, true, fields["alert"])
+	})
+}
+
+func TestLevelDefaultsExplicitFieldsWin(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelDefaults(WarnLevel, Fields{"alert": true, "team": "ops"})
+		log.WithField("alert", false).Error("test")
+	}, func(fields Fields) {
+		assert.Equal(t, false, fields["alert"])
+		assert.Equal(t, "ops", fields["team"])
+	})
+}
+
+func TestLevelDefaultsClosestThresholdWins(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelDefaults(InfoLevel, Fields{"page": "no", "info": true})
+		log.SetLevelDefaults(ErrorLevel, Fields{"page": "yes"})
+		log.Error("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "yes", fields["page"])
+		assert.Equal(t, true, fields["info"])
+	})
+}
+
+func TestLevelStack(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelStack(ErrorLevel, true)
+		log.Warn("test")
+	}, func(fields Fields) {
+		_, ok := fields[StacktraceKey]
+		assert.False(t, ok)
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelStack(ErrorLevel, true)
+		log.Error("test")
+	}, func(fields Fields) {
+		assert.NotEmpty(t, fields[StacktraceKey])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelStack(ErrorLevel, true)
+		log.WithField(StacktraceKey, "explicit").Error("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "explicit", fields[StacktraceKey])
+	})
+}
+
+func TestLevelDefaultsRemoved(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetLevelDefaults(ErrorLevel, Fields{"alert": true})
+		log.SetLevelStack(ErrorLevel, true)
+		log.SetLevelDefaults(ErrorLevel, nil)
+		log.SetLevelStack(ErrorLevel, false)
+		log.Error("test")
+	}, func(fields Fields) {
+		assert.Equal(t, 3, len(fields), "should only have msg/time/level fields")
+	})
+}
+
+func TestWithFieldAtLevel(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Level = DebugLevel
+		log.WithField("a", 1).WithFieldAtLevel(DebugLevel, "internals", "verbose").Debug("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "verbose", fields["internals"])
+		assert.Equal(t, 1.0, fields["a"])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Level = DebugLevel
+		log.WithField("a", 1).WithFieldAtLevel(DebugLevel, "internals", "verbose").Info("test")
+	}, func(fields Fields) {
+		_, ok := fields["internals"]
+		assert.False(t, ok, "debug-only fields should not be logged at info")
+		assert.Equal(t, 1.0, fields["a"])
+	})
+}
+
+func TestWithFieldAtLevelKeptByDerivedEntries(t *testing.T) {
+	base := NewEntry(New()).WithFieldAtLevel(DebugLevel, "internals", "verbose")
+	first := base.WithFieldAtLevel(TraceLevel, "first", 1)
+	second := base.WithField("b", 2).WithFieldAtLevel(TraceLevel, "second", 2)
+
+	assert.Equal(t, Fields{"internals": "verbose", "first": 1}, first.fieldsAtLevel(TraceLevel))
+	assert.Equal(t, Fields{"internals": "verbose", "b": 2, "second": 2}, second.fieldsAtLevel(TraceLevel))
+	assert.Equal(t, Fields{"internals": "verbose", "b": 2}, second.fieldsAtLevel(DebugLevel))
+}
+
+func TestSetLevelDefaultsWhileLogging(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			logger.SetLevelDefaults(ErrorLevel, Fields{"alert": i})
+			logger.SetLevelStack(ErrorLevel, i%2 == 0)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		logger.Error("test")
+	}
+	<-done
+}
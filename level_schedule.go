@@ -0,0 +1,54 @@
+package logrus
+
+import "time"
+
+// ScheduleEntry is a daily time window in which the logger uses Level instead
+// of its own level. Start and End are offsets from midnight in the location of
+// the times returned by the logger's clock, a window whose End is before its
+// Start wraps around midnight, e.g. 20h to 6h.
+type ScheduleEntry struct {
+	Start time.Duration
+	End   time.Duration
+	Level Level
+}
+
+func (s ScheduleEntry) contains(offset time.Duration) bool {
+	if s.Start <= s.End {
+		return offset >= s.Start && offset < s.End
+	}
+	return offset >= s.Start || offset < s.End
+}
+
+// SetLevelSchedule sets the windows switching the level of the logger during
+// the day, e.g. DebugLevel during business hours and WarnLevel overnight. The
+// current window is looked up with the logger's clock, see SetClock, and when
+// windows overlap the most restrictive level wins. Outside of every window the
+// logger's level applies, and the levels set with SetModuleLevel always win.
+// Passing nil removes the schedule.
+func (logger *Logger) SetLevelSchedule(schedule []ScheduleEntry) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.LevelSchedule = schedule
+}
+
+// scheduledLevel returns the level of the windows of LevelSchedule containing
+// the current time of the logger's clock.
+func (logger *Logger) scheduledLevel() (Level, bool) {
+	schedule := logger.LevelSchedule
+	if len(schedule) == 0 {
+		return 0, false
+	}
+	now := logger.now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	offset := now.Sub(midnight)
+
+	var level Level
+	found := false
+	for _, s := range schedule {
+		if s.contains(offset) && (!found || s.Level.severity() < level.severity()) {
+			level = s.Level
+			found = true
+		}
+	}
+	return level, found
+}
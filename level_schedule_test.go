@@ -0,0 +1,50 @@
+package logrus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelScheduleCrossesWindowBoundary(t *testing.T) {
+	now := time.Date(2020, 1, 1, 17, 59, 59, 0, time.UTC)
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.SetClock(func() time.Time { return now })
+	logger.SetLevelSchedule([]ScheduleEntry{
+		{Start: 9 * time.Hour, End: 18 * time.Hour, Level: DebugLevel},
+		{Start: 18 * time.Hour, End: 9 * time.Hour, Level: WarnLevel},
+	})
+
+	logger.Debug("business hours")
+	assert.Contains(t, buffer.String(), "business hours")
+
+	buffer.Reset()
+	now = now.Add(time.Second)
+	logger.Debug("overnight")
+	logger.Info("overnight")
+	assert.Equal(t, 0, buffer.Len(), "debug and info should be dropped overnight")
+
+	now = now.Add(15 * time.Hour)
+	logger.Debug("next morning")
+	assert.Contains(t, buffer.String(), "next morning")
+}
+
+func TestLevelScheduleOverlapMostRestrictive(t *testing.T) {
+	logger := New()
+	logger.SetClock(func() time.Time { return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC) })
+	logger.SetLevelSchedule([]ScheduleEntry{
+		{Start: 0, End: 24 * time.Hour, Level: DebugLevel},
+		{Start: 11 * time.Hour, End: 13 * time.Hour, Level: ErrorLevel},
+	})
+	assert.Equal(t, ErrorLevel, logger.level())
+
+	logger.SetModuleLevel("db", TraceLevel)
+	assert.Equal(t, TraceLevel, logger.level("db"), "module levels should win over the schedule")
+
+	logger.SetLevelSchedule(nil)
+	assert.Equal(t, InfoLevel, logger.level())
+}
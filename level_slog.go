@@ -0,0 +1,52 @@
+// +build go1.21
+
+package logrus
+
+import "log/slog"
+
+// SlogLevel converts the Level to the corresponding slog.Level. slog only
+// defines debug, info, warn and error, the remaining levels are placed around
+// them keeping the ordering: TraceLevel becomes LevelDebug-4, FatalLevel
+// LevelError+4 and PanicLevel LevelError+8.
+func (level Level) SlogLevel() slog.Level {
+	switch standardLevel(level) {
+	case TraceLevel:
+		return slog.LevelDebug - 4
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError + 8
+	}
+}
+
+// LevelFromSlog converts a slog.Level to a Level, the reverse of SlogLevel.
+// slog levels are integers, a level in between two of those returned by
+// SlogLevel maps to the less severe of the two, e.g. LevelInfo+2 is InfoLevel.
+// Anything below LevelDebug is TraceLevel and anything from LevelError+8 up is
+// PanicLevel.
+func LevelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	case level < slog.LevelError+4:
+		return ErrorLevel
+	case level < slog.LevelError+8:
+		return FatalLevel
+	default:
+		return PanicLevel
+	}
+}
@@ -0,0 +1,37 @@
+// +build go1.21
+
+package logrus
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug-4, TraceLevel.SlogLevel())
+	assert.Equal(t, slog.LevelDebug, DebugLevel.SlogLevel())
+	assert.Equal(t, slog.LevelInfo, InfoLevel.SlogLevel())
+	assert.Equal(t, slog.LevelWarn, WarnLevel.SlogLevel())
+	assert.Equal(t, slog.LevelError, ErrorLevel.SlogLevel())
+	assert.Equal(t, slog.LevelError+4, FatalLevel.SlogLevel())
+	assert.Equal(t, slog.LevelError+8, PanicLevel.SlogLevel())
+}
+
+func TestSlogLevelRoundTrip(t *testing.T) {
+	for _, level := range AllLevels {
+		assert.Equal(t, level, LevelFromSlog(level.SlogLevel()), "round trip of %s", level)
+	}
+}
+
+func TestLevelFromSlogInBetweenLevels(t *testing.T) {
+	assert.Equal(t, TraceLevel, LevelFromSlog(slog.LevelDebug-8))
+	assert.Equal(t, TraceLevel, LevelFromSlog(slog.LevelDebug-1))
+	assert.Equal(t, DebugLevel, LevelFromSlog(slog.LevelDebug+2))
+	assert.Equal(t, InfoLevel, LevelFromSlog(slog.LevelInfo+2))
+	assert.Equal(t, WarnLevel, LevelFromSlog(slog.LevelWarn+2))
+	assert.Equal(t, ErrorLevel, LevelFromSlog(slog.LevelError+2))
+	assert.Equal(t, FatalLevel, LevelFromSlog(slog.LevelError+6))
+	assert.Equal(t, PanicLevel, LevelFromSlog(slog.LevelError+100))
+}
@@ -0,0 +1,58 @@
+package logrus
+
+import "unicode/utf8"
+
+// Defines the key holding the number of fields dropped because of MaxFields.
+var FieldsTruncatedKey = "fields_truncated"
+
+// Defines the marker appended to messages truncated because of MaxMessageBytes.
+var TruncatedMarker = "..."
+
+// SetMaxMessageBytes limits the size of the messages, longer messages, such as
+// the dump of a huge slice, are cut to this many bytes and TruncatedMarker is
+// appended. 0 means no limit, the default.
+func (logger *Logger) SetMaxMessageBytes(max int) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.MaxMessageBytes = max
+}
+
+// SetMaxFields limits the number of fields of the entries. The fields placed
+// last by Entry.SortedKeys are dropped from larger entries and their number
+// is recorded under FieldsTruncatedKey, which counts as one of the max
+// fields. 0 means no limit, the default.
+func (logger *Logger) SetMaxFields(max int) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.MaxFields = max
+}
+
+// truncateMessage cuts s to max bytes without splitting a UTF-8 sequence.
+func truncateMessage(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	n := max
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + TruncatedMarker
+}
+
+// truncatedFields returns the entry's data limited to MaxFields fields, the
+// FieldsTruncatedKey one included. The data is only copied when fields are
+// dropped.
+func (entry *Entry) truncatedFields() Fields {
+	max := entry.Logger.MaxFields
+	if len(entry.Data) <= max {
+		return entry.Data
+	}
+	keys := entry.SortedKeys()
+	kept := max - 1
+	data := make(Fields, max)
+	for _, k := range keys[:kept] {
+		data[k] = entry.Data[k]
+	}
+	data[FieldsTruncatedKey] = len(keys) - kept
+	return data
+}
@@ -0,0 +1,65 @@
+package logrus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxMessageBytes(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetMaxMessageBytes(8)
+		log.Info(strings.Repeat("x", 100))
+	}, func(fields Fields) {
+		assert.Equal(t, "xxxxxxxx"+TruncatedMarker, fields["msg"])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetMaxMessageBytes(8)
+		log.Info("short")
+	}, func(fields Fields) {
+		assert.Equal(t, "short", fields["msg"])
+	})
+}
+
+func TestMaxMessageBytesKeepsRunes(t *testing.T) {
+	assert.Equal(t, "ab"+TruncatedMarker, truncateMessage("abédf", 3))
+}
+
+func TestMaxFields(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetMaxFields(3)
+		log.WithFields(Fields{"a": 1, "b": 2, "c": 3, "d": 4}).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, float64(1), fields["a"])
+		assert.Equal(t, float64(2), fields["b"])
+		assert.NotContains(t, fields, "c")
+		assert.NotContains(t, fields, "d")
+		assert.Equal(t, float64(2), fields[FieldsTruncatedKey])
+	})
+}
+
+func TestMaxFieldsCountsMarker(t *testing.T) {
+	logger := New()
+	logger.SetMaxFields(3)
+	entry := logger.WithFields(Fields{"a": 1, "b": 2, "c": 3, "d": 4})
+	assert.Equal(t, Fields{"a": 1, "b": 2, FieldsTruncatedKey: 2}, entry.truncatedFields())
+
+	logger.SetMaxFields(4)
+	assert.Len(t, entry.truncatedFields(), 4, "an entry within the limit is kept whole")
+
+	logger.SetMaxFields(1)
+	assert.Equal(t, Fields{FieldsTruncatedKey: 4}, entry.truncatedFields())
+}
+
+func TestMaxFieldsDoesNotModifyEntry(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.SetMaxFields(1)
+	entry := logger.WithFields(Fields{"a": 1, "b": 2})
+	entry.Info("test")
+	assert.Len(t, entry.Data, 2)
+}
@@ -1,19 +1,23 @@
 package logrus
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"regexp"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type Logger struct {
 	// The logs are `io.Copy`'d to this in a mutex. It's common to set this to a
 	// file, or leave it default which is `os.Stderr`. You can also set this to
-	// something more adventorous, such as logging to Kafka.
+	// something more adventorous, such as logging to Kafka. When nil, entries
+	// are written to `os.Stderr`.
 	Out io.Writer
 	// Hooks for the logger instance. These allow firing events based on logging
 	// levels and log entries. For example, to send errors to an error tracking
@@ -34,10 +38,104 @@ type Logger struct {
 	mu MutexWrap
 	// Reusable empty entry
 	entryPool sync.Pool
-	//Automatically attach the stacktrace for WithError
-	StackOnError bool
+	//Don't capture the stacktrace of the plain errors added with WithError,
+	//see SetStackOnError
+	DisableStackOnError bool
 	//Set logging level per module
 	ModuleLevels map[string]Level
+	//Guards ModuleLevels
+	moduleLevelsMu sync.RWMutex
+	//Match module names regardless of case when resolving module levels and
+	//outputs
+	CaseInsensitiveModules bool
+	//Report malformed `With` calls in the FieldErrorKey field instead of
+	//silently fixing them up
+	StrictFields bool
+	//Attach the id of the logging goroutine to every entry, see GoroutineIDKey
+	ReportGoroutineID bool
+	//Attach the hostname, pid and Version to every entry, see SetProcessFields
+	ProcessFields bool
+	//Fields read from the environment added to every entry, see SetFieldsFromEnv
+	EnvFields Fields
+	//Version of the application, see SetVersion
+	Version string
+	//Attach the file, line and function of the caller to every entry
+	ReportCaller bool
+	//Number of extra frames skipped when reporting the caller, see SetReportCallerDepth
+	ReportCallerDepth int
+	//Extract fields from the context of entries created with WithContext
+	ContextExtractor func(context.Context) Fields
+	//Keys placed first, in this order, by the formatters, see Entry.SortedKeys
+	FieldOrder []string
+	//Called with the errors of hooks, formatters and writers, see SetErrorHandler
+	ErrorHandler func(error)
+	//Write an entry describing the formatter and the field names before the
+	//first entry, so log tooling can detect the format, see FormatMetaKey
+	EmitFormatMeta bool
+	//Version of the schema of the entries, added to the format meta entry
+	SchemaVersion string
+	//Entries kept until Replay, see StartBuffering
+	bootstrap *entryRing
+	buffering int32
+	//Writes the format meta entry once
+	formatMetaOnce sync.Once
+	//Maximum depth of the nested values dumped in messages, 0 means no limit
+	MaxDumpDepth int
+	//Builds the messages from the arguments of Info and co, see SetArgJoiner
+	ArgJoiner func(args []interface{}) string
+	//Patterns of secrets masked in messages and string fields, see AddSecretPattern
+	SecretPatterns []*regexp.Regexp
+	//Returns the time of entries, defaults to time.Now, see SetClock
+	Clock func() time.Time
+	//Fields added to entries at or above a level, see SetLevelDefaults
+	LevelDefaults map[Level]Fields
+	//Attach the stacktrace to entries at or above a level, see SetLevelStack
+	LevelStacks map[Level]bool
+	//Levels used during some windows of the day, see SetLevelSchedule
+	LevelSchedule []ScheduleEntry
+	//Maximum size of the messages in bytes, 0 means no limit, see SetMaxMessageBytes
+	MaxMessageBytes int
+	//Maximum number of fields of the entries, 0 means no limit, see SetMaxFields
+	MaxFields int
+	//Maximum number of bytes formatted of the WithBinary fields, 0 means no
+	//limit, see SetMaxBinaryBytes
+	MaxBinaryBytes int
+	//Outputs written with their own formatter in addition to Out, see AddSink
+	Sinks []Sink
+	//Outputs of the modules writing elsewhere than Out, see SetModuleOutput
+	ModuleOutputs map[string]io.Writer
+	//Least severe level at which the stacktraces of the errors added with
+	//WithError are logged, PanicLevel, the zero value, means ErrorLevel unless
+	//set with SetStacktraceMinLevel
+	StacktraceMinLevel Level
+	//Formats the entries the Formatter fails to format, see SetFallbackFormatter
+	FallbackFormatter Formatter
+	//Exit code of Fatal, 0 means 1, see SetFatalExitCode
+	FatalExitCode int
+	//Panic instead of logging at ErrorLevel when an assertion fails, see Entry.Assert
+	AssertPanics bool
+	//Receives the entries instead of the Formatter and Out, see SetEventSink
+	EventSink func(LogEvent) error
+	//Digits after the decimal point of WithFloat values without a precision, 0
+	//means the shortest representation and NoDecimals none, see
+	//SetFloatPrecision
+	FloatPrecision int
+	//Reuse the copies of the entries made when logging, see SetPoolEntries
+	PoolEntries bool
+	//Number of goroutines calling the hooks and the outputs, see nestedEmit
+	hookCalls int32
+	outCalls  int32
+	//Identifies the logger in the reentrancy guards, see guardID
+	id uint32
+	//SecretPatterns as last set by AddSecretPattern, read while logging
+	secretPatterns atomic.Value
+	//LevelDefaults and LevelStacks as last set by their setters, read while
+	//logging
+	levelDefaults atomic.Value
+	//Set by SetStacktraceMinLevel(PanicLevel), see stacktraceMinLevel
+	panicStacktracesOnly bool
+	//Set by Close, entries are then written to stderr
+	closed int32
 }
 
 type MutexWrap struct {
@@ -99,6 +197,21 @@ func (logger *Logger) NewModule(moduleName string) *Entry {
 	return logger.WithField(ModuleNameKey, moduleName)
 }
 
+// WithModule creates an entry of a module, using the value defined in
+// ModuleNameKey as key, like NewModule.
+func (logger *Logger) WithModule(moduleName string) *Entry {
+	return logger.WithField(ModuleNameKey, moduleName)
+}
+
+// Returns an entry of the logger when cond is true and otherwise an entry on
+// which the logging functions do nothing, see Entry.If.
+func (logger *Logger) If(cond bool) *Entry {
+	if !cond {
+		return noopEntry
+	}
+	return NewEntry(logger)
+}
+
 // Adds a field to the log entry, note that it doesn't log until you call
 // Debug, Print, Info, Warn, Fatal or Panic. It only creates a log entry.
 // If you want multiple fields, use `WithFields`.
@@ -108,6 +221,27 @@ func (logger *Logger) WithField(key string, value interface{}) *Entry {
 	return entry.WithField(key, value)
 }
 
+// Adds fields output in the order they are given to the log entry, see
+// Entry.WithOrderedFields.
+func (logger *Logger) WithOrderedFields(fields OrderedFields) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithOrderedFields(fields)
+}
+
+// Adds a field with a formatted value to the log entry, see Entry.WithFieldf.
+func (logger *Logger) WithFieldf(key string, format string, args ...interface{}) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithFieldf(key, format, args...)
+}
+
+// Assert logs msg at ErrorLevel, or panics, when cond is false, see
+// Entry.Assert.
+func (logger *Logger) Assert(cond bool, msg string) *Entry {
+	return NewEntry(logger).Assert(cond, msg)
+}
+
 // Adds a struct of fields to the log entry. All it does is call `WithField` for
 // each `Field`.
 func (logger *Logger) WithFields(fields Fields) *Entry {
@@ -124,8 +258,70 @@ func (logger *Logger) WithError(err error) *Entry {
 	return entry.WithError(err)
 }
 
+// Add several errors as a single field to the log entry. All it does is call
+// `WithErrors` for the given errors.
+func (logger *Logger) WithErrors(errs ...error) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithErrors(errs...)
+}
+
+// Add a context to the log entry. All it does is call `WithContext` for the
+// given context.
+func (logger *Logger) WithContext(ctx context.Context) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithContext(ctx)
+}
+
+// Adds the id of the request being served to the log entry, see
+// Entry.WithRequestID.
+func (logger *Logger) WithRequestID(id string) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithRequestID(id)
+}
+
+// Set the time of the log entry. All it does is call `WithTime` for the given
+// time.
+func (logger *Logger) WithTime(t time.Time) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithTime(t)
+}
+
+// Add the exported fields of a struct to the log entry. All it does is call
+// `WithStruct` for the given value.
+func (logger *Logger) WithStruct(v interface{}) *Entry {
+	entry := logger.newEntry()
+	defer logger.releaseEntry(entry)
+	return entry.WithStruct(v)
+}
+
+// Log logs at a level chosen at runtime, see Entry.Log.
+func (logger *Logger) Log(level Level, args ...interface{}) {
+	entry := logger.newEntry()
+	entry.Log(level, args...)
+	logger.releaseEntry(entry)
+}
+
+// LogIf logs at a level chosen at runtime when cond is true, see Entry.LogIf.
+func (logger *Logger) LogIf(cond bool, level Level, args ...interface{}) {
+	if !cond {
+		return
+	}
+	logger.Log(level, args...)
+}
+
+// Logf logs at a level chosen at runtime, see Entry.Logf.
+func (logger *Logger) Logf(level Level, format string, args ...interface{}) {
+	entry := logger.newEntry()
+	entry.Logf(level, format, args...)
+	logger.releaseEntry(entry)
+}
+
 func (logger *Logger) Tracef(format string, args ...interface{}) {
-	if logger.level() >= TraceLevel {
+	if logger.level().enables(TraceLevel) {
 		entry := logger.newEntry()
 		entry.Tracef(format, args...)
 		logger.releaseEntry(entry)
@@ -133,7 +329,7 @@ func (logger *Logger) Tracef(format string, args ...interface{}) {
 }
 
 func (logger *Logger) Debugf(format string, args ...interface{}) {
-	if logger.level() >= DebugLevel {
+	if logger.level().enables(DebugLevel) {
 		entry := logger.newEntry()
 		entry.Debugf(format, args...)
 		logger.releaseEntry(entry)
@@ -141,7 +337,7 @@ func (logger *Logger) Debugf(format string, args ...interface{}) {
 }
 
 func (logger *Logger) Infof(format string, args ...interface{}) {
-	if logger.level() >= InfoLevel {
+	if logger.level().enables(InfoLevel) {
 		entry := logger.newEntry()
 		entry.Infof(format, args...)
 		logger.releaseEntry(entry)
@@ -149,13 +345,15 @@ func (logger *Logger) Infof(format string, args ...interface{}) {
 }
 
 func (logger *Logger) Printf(format string, args ...interface{}) {
-	entry := logger.newEntry()
-	entry.Printf(format, args...)
-	logger.releaseEntry(entry)
+	if logger.level().enables(InfoLevel) {
+		entry := logger.newEntry()
+		entry.Printf(format, args...)
+		logger.releaseEntry(entry)
+	}
 }
 
 func (logger *Logger) Warnf(format string, args ...interface{}) {
-	if logger.level() >= WarnLevel {
+	if logger.level().enables(WarnLevel) {
 		entry := logger.newEntry()
 		entry.Warnf(format, args...)
 		logger.releaseEntry(entry)
@@ -163,7 +361,7 @@ func (logger *Logger) Warnf(format string, args ...interface{}) {
 }
 
 func (logger *Logger) Warningf(format string, args ...interface{}) {
-	if logger.level() >= WarnLevel {
+	if logger.level().enables(WarnLevel) {
 		entry := logger.newEntry()
 		entry.Warnf(format, args...)
 		logger.releaseEntry(entry)
@@ -171,7 +369,7 @@ func (logger *Logger) Warningf(format string, args ...interface{}) {
 }
 
 func (logger *Logger) Errorf(format string, args ...interface{}) {
-	if logger.level() >= ErrorLevel {
+	if logger.level().enables(ErrorLevel) {
 		entry := logger.newEntry()
 		entry.Errorf(format, args...)
 		logger.releaseEntry(entry)
@@ -179,16 +377,16 @@ func (logger *Logger) Errorf(format string, args ...interface{}) {
 }
 
 func (logger *Logger) Fatalf(format string, args ...interface{}) {
-	if logger.level() >= FatalLevel {
+	if logger.level().enables(FatalLevel) {
 		entry := logger.newEntry()
 		entry.Fatalf(format, args...)
 		logger.releaseEntry(entry)
 	}
-	Exit(1)
+	Exit(logger.fatalExitCode())
 }
 
 func (logger *Logger) Panicf(format string, args ...interface{}) {
-	if logger.level() >= PanicLevel {
+	if logger.level().enables(PanicLevel) {
 		entry := logger.newEntry()
 		entry.Panicf(format, args...)
 		logger.releaseEntry(entry)
@@ -196,7 +394,7 @@ func (logger *Logger) Panicf(format string, args ...interface{}) {
 }
 
 func (logger *Logger) Trace(args ...interface{}) {
-	if logger.level() >= TraceLevel {
+	if logger.level().enables(TraceLevel) {
 		entry := logger.newEntry()
 		entry.Trace(args...)
 		logger.releaseEntry(entry)
@@ -204,7 +402,7 @@ func (logger *Logger) Trace(args ...interface{}) {
 }
 
 func (logger *Logger) Debug(args ...interface{}) {
-	if logger.level() >= DebugLevel {
+	if logger.level().enables(DebugLevel) {
 		entry := logger.newEntry()
 		entry.Debug(args...)
 		logger.releaseEntry(entry)
@@ -212,7 +410,7 @@ func (logger *Logger) Debug(args ...interface{}) {
 }
 
 func (logger *Logger) Info(args ...interface{}) {
-	if logger.level() >= InfoLevel {
+	if logger.level().enables(InfoLevel) {
 		entry := logger.newEntry()
 		entry.Info(args...)
 		logger.releaseEntry(entry)
@@ -220,13 +418,15 @@ func (logger *Logger) Info(args ...interface{}) {
 }
 
 func (logger *Logger) Print(args ...interface{}) {
-	entry := logger.newEntry()
-	entry.Info(args...)
-	logger.releaseEntry(entry)
+	if logger.level().enables(InfoLevel) {
+		entry := logger.newEntry()
+		entry.Info(args...)
+		logger.releaseEntry(entry)
+	}
 }
 
 func (logger *Logger) Warn(args ...interface{}) {
-	if logger.level() >= WarnLevel {
+	if logger.level().enables(WarnLevel) {
 		entry := logger.newEntry()
 		entry.Warn(args...)
 		logger.releaseEntry(entry)
@@ -234,7 +434,7 @@ func (logger *Logger) Warn(args ...interface{}) {
 }
 
 func (logger *Logger) Warning(args ...interface{}) {
-	if logger.level() >= WarnLevel {
+	if logger.level().enables(WarnLevel) {
 		entry := logger.newEntry()
 		entry.Warn(args...)
 		logger.releaseEntry(entry)
@@ -242,7 +442,7 @@ func (logger *Logger) Warning(args ...interface{}) {
 }
 
 func (logger *Logger) Error(args ...interface{}) {
-	if logger.level() >= ErrorLevel {
+	if logger.level().enables(ErrorLevel) {
 		entry := logger.newEntry()
 		entry.Error(args...)
 		logger.releaseEntry(entry)
@@ -250,16 +450,16 @@ func (logger *Logger) Error(args ...interface{}) {
 }
 
 func (logger *Logger) Fatal(args ...interface{}) {
-	if logger.level() >= FatalLevel {
+	if logger.level().enables(FatalLevel) {
 		entry := logger.newEntry()
 		entry.Fatal(args...)
 		logger.releaseEntry(entry)
 	}
-	Exit(1)
+	Exit(logger.fatalExitCode())
 }
 
 func (logger *Logger) Panic(args ...interface{}) {
-	if logger.level() >= PanicLevel {
+	if logger.level().enables(PanicLevel) {
 		entry := logger.newEntry()
 		entry.Panic(args...)
 		logger.releaseEntry(entry)
@@ -267,7 +467,7 @@ func (logger *Logger) Panic(args ...interface{}) {
 }
 
 func (logger *Logger) Traceln(args ...interface{}) {
-	if logger.level() >= TraceLevel {
+	if logger.level().enables(TraceLevel) {
 		entry := logger.newEntry()
 		entry.Traceln(args...)
 		logger.releaseEntry(entry)
@@ -275,7 +475,7 @@ func (logger *Logger) Traceln(args ...interface{}) {
 }
 
 func (logger *Logger) Debugln(args ...interface{}) {
-	if logger.level() >= DebugLevel {
+	if logger.level().enables(DebugLevel) {
 		entry := logger.newEntry()
 		entry.Debugln(args...)
 		logger.releaseEntry(entry)
@@ -283,7 +483,7 @@ func (logger *Logger) Debugln(args ...interface{}) {
 }
 
 func (logger *Logger) Infoln(args ...interface{}) {
-	if logger.level() >= InfoLevel {
+	if logger.level().enables(InfoLevel) {
 		entry := logger.newEntry()
 		entry.Infoln(args...)
 		logger.releaseEntry(entry)
@@ -291,13 +491,15 @@ func (logger *Logger) Infoln(args ...interface{}) {
 }
 
 func (logger *Logger) Println(args ...interface{}) {
-	entry := logger.newEntry()
-	entry.Println(args...)
-	logger.releaseEntry(entry)
+	if logger.level().enables(InfoLevel) {
+		entry := logger.newEntry()
+		entry.Println(args...)
+		logger.releaseEntry(entry)
+	}
 }
 
 func (logger *Logger) Warnln(args ...interface{}) {
-	if logger.level() >= WarnLevel {
+	if logger.level().enables(WarnLevel) {
 		entry := logger.newEntry()
 		entry.Warnln(args...)
 		logger.releaseEntry(entry)
@@ -305,7 +507,7 @@ func (logger *Logger) Warnln(args ...interface{}) {
 }
 
 func (logger *Logger) Warningln(args ...interface{}) {
-	if logger.level() >= WarnLevel {
+	if logger.level().enables(WarnLevel) {
 		entry := logger.newEntry()
 		entry.Warnln(args...)
 		logger.releaseEntry(entry)
@@ -313,7 +515,7 @@ func (logger *Logger) Warningln(args ...interface{}) {
 }
 
 func (logger *Logger) Errorln(args ...interface{}) {
-	if logger.level() >= ErrorLevel {
+	if logger.level().enables(ErrorLevel) {
 		entry := logger.newEntry()
 		entry.Errorln(args...)
 		logger.releaseEntry(entry)
@@ -321,37 +523,158 @@ func (logger *Logger) Errorln(args ...interface{}) {
 }
 
 func (logger *Logger) Fatalln(args ...interface{}) {
-	if logger.level() >= FatalLevel {
+	if logger.level().enables(FatalLevel) {
 		entry := logger.newEntry()
 		entry.Fatalln(args...)
 		logger.releaseEntry(entry)
 	}
-	Exit(1)
+	Exit(logger.fatalExitCode())
 }
 
 func (logger *Logger) Panicln(args ...interface{}) {
-	if logger.level() >= PanicLevel {
+	if logger.level().enables(PanicLevel) {
 		entry := logger.newEntry()
 		entry.Panicln(args...)
 		logger.releaseEntry(entry)
 	}
 }
 
+// SetLevel sets the level of the logger, used by the default module and the
+// modules without a level of their own, see SetModuleLevel.
+func (logger *Logger) SetLevel(level Level) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.setLevel(level)
+}
+
+// GetLevel returns the level of the logger, the one of the default module. It
+// is the level of the current window of the level schedule, if any.
+func (logger *Logger) GetLevel() Level {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	return logger.level()
+}
+
+// SetModuleLevel sets the logging level of a module. The name may be a glob
+// pattern, with the syntax of path.Match, e.g. `db.*` sets the level of all
+// the modules under db. A module uses the level of its exact name first, then
+// of the longest matching pattern, then the logger's level.
 func (logger *Logger) SetModuleLevel(moduleName string, level Level) {
 	if moduleName != DefaultModuleName {
-		logger.ModuleLevels[moduleName] = level
+		logger.moduleLevelsMu.Lock()
+		logger.ModuleLevels[logger.moduleKey(moduleName)] = level
+		logger.moduleLevelsMu.Unlock()
 	} else {
 		logger.setLevel(level)
 	}
 }
 
+// SetStackOnError sets whether WithError captures the stacktrace of the errors
+// which don't carry their own, unlike an *errors.Error. It is enabled by
+// default, capturing the stacktrace allocates, hot paths logging plain errors
+// may disable it and only get the error field.
+func (logger *Logger) SetStackOnError(enable bool) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.DisableStackOnError = !enable
+}
+
+// SetPoolEntries makes the logger reuse the copy of the entry, and of its
+// fields, made every time an entry is logged, saving allocations on hot
+// paths. The copies are reused once written unless hooks fired for the level,
+// as they may keep the entry, or the logger is buffering. The formatters and
+// writers must not keep the entry or its fields. Disabled by default.
+func (logger *Logger) SetPoolEntries(enable bool) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.PoolEntries = enable
+}
+
+// SetFallbackFormatter sets the formatter used for the entries the logger's
+// formatter fails to format, e.g. a TextFormatter when some fields can't be
+// marshaled to JSON, so the entries are still written. When both fail the
+// error is reported like without a fallback.
+func (logger *Logger) SetFallbackFormatter(formatter Formatter) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.FallbackFormatter = formatter
+}
+
+// SetFatalExitCode sets the code the program exits with after a Fatal entry,
+// 1 by default.
+func (logger *Logger) SetFatalExitCode(code int) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.FatalExitCode = code
+}
+
+func (logger *Logger) fatalExitCode() int {
+	if logger.FatalExitCode == 0 {
+		return 1
+	}
+	return logger.FatalExitCode
+}
+
+// SetStacktraceMinLevel sets the least severe level at which the stacktraces
+// of the errors added with WithError are logged, ErrorLevel by default, so
+// the expected errors logged as warnings don't bloat the logs.
+func (logger *Logger) SetStacktraceMinLevel(level Level) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.StacktraceMinLevel = level
+	logger.panicStacktracesOnly = level == PanicLevel
+}
+
+func (logger *Logger) stacktraceMinLevel() Level {
+	if logger.StacktraceMinLevel == PanicLevel && !logger.panicStacktracesOnly {
+		return ErrorLevel
+	}
+	return logger.StacktraceMinLevel
+}
+
+// SetModuleOutput sets the writer of the entries of a module, e.g. to write
+// the entries of an audit module to a dedicated file, the entries of the other
+// modules are written to Out. Writes are synchronized with the ones to Out.
+// The name must be the exact module name. Passing a nil writer routes the
+// module back to Out.
+func (logger *Logger) SetModuleOutput(moduleName string, w io.Writer) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	key := logger.moduleKey(moduleName)
+	if w == nil {
+		delete(logger.ModuleOutputs, key)
+		return
+	}
+	if logger.ModuleOutputs == nil {
+		logger.ModuleOutputs = make(map[string]io.Writer)
+	}
+	logger.ModuleOutputs[key] = w
+}
+
+// moduleOutput returns the writer of the module, Out by default. It must be
+// called with mu held.
+func (logger *Logger) moduleOutput(moduleName string) io.Writer {
+	key := logger.moduleKey(moduleName)
+	if w, ok := logger.ModuleOutputs[key]; ok {
+		return w
+	}
+	if logger.CaseInsensitiveModules {
+		for name, w := range logger.ModuleOutputs {
+			if logger.moduleKey(name) == key {
+				return w
+			}
+		}
+	}
+	return logger.Out
+}
+
 func (logger *Logger) SetModuleLevelString(levelstr string) error {
 	levelstr = strings.TrimSpace(levelstr)
 	if len(levelstr) <= 0 {
 		return nil
 	}
 	if level, err := ParseLevel(levelstr); err == nil {
-		SetLevel(level)
+		logger.SetLevel(level)
 		return nil
 	}
 
@@ -381,29 +704,167 @@ func (logger *Logger) SetModuleLevelString(levelstr string) error {
 }
 
 func (logger *Logger) ClearModuleLevels() {
+	logger.moduleLevelsMu.Lock()
+	defer logger.moduleLevelsMu.Unlock()
 	for k := range logger.ModuleLevels {
 		delete(logger.ModuleLevels, k)
 	}
 }
 
-//When file is opened with appending mode, it's safe to
-//write concurrently to a file (within 4k message on Linux).
-//In these cases user can choose to disable the lock.
+// When file is opened with appending mode, it's safe to
+// write concurrently to a file (within 4k message on Linux).
+// In these cases user can choose to disable the lock.
 func (logger *Logger) SetNoLock() {
 	logger.mu.Disable()
 }
 
-//if name is specified, then return the correspoindg logging level for the specified module
-//if name is not specifed, returns the default logging level
+// SetReportCaller enables or disables attaching the caller's file, line and
+// function (using the keys FileKey and FuncKey) to every entry.
+func (logger *Logger) SetReportCaller(enable bool) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.ReportCaller = enable
+}
+
+// SetReportCallerDepth sets how many frames are skipped above the call into
+// logrus when reporting the caller. The default of 0 reports the code calling
+// Info, Warn, etc. directly, no matter how many wrappers (Print, Warning, the
+// package level functions) logrus goes through internally. If you wrap the
+// logger in your own helpers, set it to the number of wrapper layers so that
+// the caller of your helper is reported instead.
+func (logger *Logger) SetReportCallerDepth(depth int) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.ReportCallerDepth = depth
+}
+
+// SetContextExtractor sets the function used to extract fields from the
+// context of entries created with WithContext. This keeps logrus independent
+// of any tracing library while still allowing to correlate logs with traces,
+// e.g. by returning the trace and span ids found in the context (using
+// TraceIDKey, SpanIDKey and ParentSpanIDKey, like WithTraceContext does).
+// Extracted fields never override fields set explicitly on the entry.
+func (logger *Logger) SetContextExtractor(extractor func(context.Context) Fields) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.ContextExtractor = extractor
+}
+
+// SetReportGoroutineID enables or disables attaching the goroutine id to every
+// entry. Extracting the id requires parsing the runtime stack, so leave it
+// disabled unless you are debugging concurrency issues.
+func (logger *Logger) SetReportGoroutineID(enable bool) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.ReportGoroutineID = enable
+}
+
+// SetErrorHandler sets the function called when a hook fails, e.g. loses
+// its connection, or when an entry can't be formatted or written. It must
+// not log with the same logger at a level which may fail again. Passing nil
+// restores the default, which prints the errors to stderr.
+func (logger *Logger) SetErrorHandler(handler func(error)) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.ErrorHandler = handler
+}
+
+// handleError reports an error which happened while logging, message is the
+// format used to print it when there is no ErrorHandler.
+func (logger *Logger) handleError(message string, err error) {
+	if logger.ErrorHandler != nil {
+		logger.ErrorHandler(err)
+		return
+	}
+	logger.mu.Lock()
+	fmt.Fprintf(os.Stderr, message, err)
+	logger.mu.Unlock()
+}
+
+// SetMaxDumpDepth limits how deep the nested maps, structs, slices and
+// pointers passed to the logging functions are dumped, e.g. to protect the
+// log size from an accidentally logged object graph. Deeper values are
+// replaced by spew's max depth marker. The default of 0 means no limit.
+func (logger *Logger) SetMaxDumpDepth(depth int) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.MaxDumpDepth = depth
+}
+
+// SetClock sets the function returning the time of the logged entries, e.g. a
+// fixed time in tests. Entries with a time set by WithTime keep it. Passing
+// nil restores time.Now.
+func (logger *Logger) SetClock(clock func() time.Time) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.Clock = clock
+}
+
+func (logger *Logger) now() time.Time {
+	if logger.Clock != nil {
+		return logger.Clock()
+	}
+	return time.Now()
+}
+
+// if name is specified, then return the correspoindg logging level for the specified module
+// if name is not specifed, returns the default logging level
 func (logger *Logger) level(name ...string) Level {
 	if len(name) > 0 {
-		if lv, ok := logger.ModuleLevels[name[0]]; ok {
+		key := logger.moduleKey(name[0])
+		logger.moduleLevelsMu.RLock()
+		lv, ok := logger.ModuleLevels[key]
+		if !ok {
+			lv, ok = logger.patternLevel(key)
+		}
+		logger.moduleLevelsMu.RUnlock()
+		if ok {
 			return lv
 		}
 	}
+	if lv, ok := logger.scheduledLevel(); ok {
+		return lv
+	}
 	return Level(atomic.LoadUint32((*uint32)(&logger.Level)))
 }
 
+// patternLevel returns the level of the longest glob pattern in ModuleLevels,
+// e.g. `db.*`, matching the module. Patterns use the syntax of path.Match.
+// With CaseInsensitiveModules the names and patterns stored without
+// SetModuleLevel, e.g. set in the map before the field, are lowercased too,
+// and a name differing from the key only by case wins over the patterns.
+// The caller holds moduleLevelsMu.
+func (logger *Logger) patternLevel(key string) (Level, bool) {
+	var level Level
+	longest := -1
+	if key == DefaultModuleName {
+		return level, false
+	}
+	for pattern, lv := range logger.ModuleLevels {
+		pattern = logger.moduleKey(pattern)
+		if pattern == key {
+			return lv, true
+		}
+		if len(pattern) <= longest || !strings.ContainsAny(pattern, "*?[") {
+			continue
+		}
+		if matched, _ := path.Match(pattern, key); matched {
+			level = lv
+			longest = len(pattern)
+		}
+	}
+	return level, longest >= 0
+}
+
+// moduleKey returns the key of a module in ModuleLevels and ModuleOutputs,
+// which is the lowercased name when CaseInsensitiveModules is set.
+func (logger *Logger) moduleKey(moduleName string) string {
+	if logger.CaseInsensitiveModules {
+		return strings.ToLower(moduleName)
+	}
+	return moduleName
+}
+
 func (logger *Logger) setLevel(level Level) {
 	atomic.StoreUint32((*uint32)(&logger.Level), uint32(level))
 }
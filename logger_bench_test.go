@@ -1,8 +1,11 @@
 package logrus
 
 import (
+	"io"
+	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 )
 
 // smallFields is a small size data set for benchmarking
@@ -31,7 +34,7 @@ func BenchmarkDummyLoggerNoLock(b *testing.B) {
 	doLoggerBenchmarkNoLock(b, nullf, &TextFormatter{DisableColors: true}, smallFields)
 }
 
-func doLoggerBenchmark(b *testing.B, out *os.File, formatter Formatter, fields Fields) {
+func doLoggerBenchmark(b *testing.B, out io.Writer, formatter Formatter, fields Fields) {
 	logger := Logger{
 		Out:       out,
 		Level:     InfoLevel,
@@ -59,3 +62,79 @@ func doLoggerBenchmarkNoLock(b *testing.B, out *os.File, formatter Formatter, fi
 		}
 	})
 }
+
+func BenchmarkBufferedLogger(b *testing.B) {
+	nullf, err := os.OpenFile("/dev/null", os.O_WRONLY, 0666)
+	if err != nil {
+		b.Fatalf("%v", err)
+	}
+	defer nullf.Close()
+	w := NewBufferedWriter(nullf, 0, time.Second)
+	defer w.Close()
+	b.SetParallelism(8)
+	doLoggerBenchmark(b, w, &TextFormatter{DisableColors: true}, smallFields)
+}
+
+func BenchmarkUnbufferedLogger(b *testing.B) {
+	nullf, err := os.OpenFile("/dev/null", os.O_WRONLY, 0666)
+	if err != nil {
+		b.Fatalf("%v", err)
+	}
+	defer nullf.Close()
+	b.SetParallelism(8)
+	doLoggerBenchmark(b, nullf, &TextFormatter{DisableColors: true}, smallFields)
+}
+
+type benchmarkBigStruct struct {
+	Names  [64]string
+	Values map[string][]int
+	Next   *benchmarkBigStruct
+}
+
+// BenchmarkDisabledLevel shows that arguments logged at a disabled level are
+// not dumped, it should report no allocations.
+func BenchmarkDisabledLevel(b *testing.B) {
+	big := &benchmarkBigStruct{Values: map[string][]int{"a": {1, 2, 3}}}
+	big.Next = &benchmarkBigStruct{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Debug(big)
+	}
+}
+
+func BenchmarkInfoLoop(b *testing.B) {
+	benchmarkInfoLoop(b, false)
+}
+
+func BenchmarkInfoLoopPoolEntries(b *testing.B) {
+	benchmarkInfoLoop(b, true)
+}
+
+func BenchmarkInfoParallel(b *testing.B) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	logger.Hooks.Add(&hookFunc{fire: func(*Entry) {}})
+	entry := logger.WithFields(loggerFields)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			entry.Info("aaa")
+		}
+	})
+}
+
+func benchmarkInfoLoop(b *testing.B, pool bool) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	logger.SetPoolEntries(pool)
+	entry := logger.WithFields(loggerFields)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entry.Info("aaa")
+	}
+}
@@ -0,0 +1,210 @@
+package logrus
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModuleLevelCaseSensitiveByDefault(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.SetModuleLevel("DB", DebugLevel)
+
+	logger.NewModule("db").Debug("lower")
+	assert.Equal(t, "", buffer.String())
+
+	logger.NewModule("DB").Debug("upper")
+	assert.Contains(t, buffer.String(), "upper")
+}
+
+func TestModuleLevelCaseInsensitive(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.CaseInsensitiveModules = true
+	logger.SetModuleLevel("DB", DebugLevel)
+
+	for _, name := range []string{"db", "Db", "DB"} {
+		buffer.Reset()
+		logger.NewModule(name).Debug("test")
+		assert.Contains(t, buffer.String(), "test", "module %q should match", name)
+	}
+
+	buffer.Reset()
+	logger.NewModule("dbx").Debug("test")
+	assert.Equal(t, "", buffer.String())
+}
+
+func TestModuleLevelCaseInsensitiveStoredNames(t *testing.T) {
+	var buffer, dbOut bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.ModuleLevels["DB"] = DebugLevel
+	logger.ModuleLevels["HTTP.*"] = TraceLevel
+	logger.SetModuleOutput("Audit", &dbOut)
+	logger.CaseInsensitiveModules = true
+
+	assert.Equal(t, DebugLevel, logger.level("db"))
+	assert.Equal(t, DebugLevel, logger.level("Db"))
+	assert.Equal(t, TraceLevel, logger.level("http.server"))
+	assert.Equal(t, InfoLevel, logger.level("dbx"))
+
+	logger.NewModule("audit").Info("audited")
+	assert.Contains(t, dbOut.String(), "audited")
+	assert.NotContains(t, buffer.String(), "audited")
+}
+
+func TestModuleLevelGlob(t *testing.T) {
+	logger := New()
+	logger.SetModuleLevel("db.*", DebugLevel)
+	logger.SetModuleLevel("db.write.*", WarnLevel)
+	logger.SetModuleLevel("db.write.audit", TraceLevel)
+
+	assert.Equal(t, DebugLevel, logger.level("db.read"))
+	assert.Equal(t, InfoLevel, logger.level("dbx"))
+	assert.Equal(t, InfoLevel, logger.level("db"))
+	assert.Equal(t, WarnLevel, logger.level("db.write.batch"), "the longest pattern should win")
+	assert.Equal(t, TraceLevel, logger.level("db.write.audit"), "exact names should win over patterns")
+	assert.Equal(t, InfoLevel, logger.level())
+
+	var buffer bytes.Buffer
+	logger.Out = &buffer
+	logger.NewModule("db.read").Debug("read")
+	logger.NewModule("dbx").Debug("dbx")
+	assert.Contains(t, buffer.String(), "read")
+	assert.NotContains(t, buffer.String(), "dbx")
+}
+
+func TestModuleOutput(t *testing.T) {
+	var main, audit bytes.Buffer
+	logger := New()
+	logger.Out = &main
+	logger.SetModuleOutput("audit", &audit)
+
+	logger.NewModule("audit").Info("user deleted")
+	logger.NewModule("db").Info("connected")
+	logger.Info("started")
+
+	assert.Contains(t, audit.String(), "user deleted")
+	assert.NotContains(t, audit.String(), "connected")
+	assert.NotContains(t, main.String(), "user deleted")
+	assert.Contains(t, main.String(), "connected")
+	assert.Contains(t, main.String(), "started")
+
+	audit.Reset()
+	logger.SetModuleOutput("audit", nil)
+	logger.NewModule("audit").Info("back to main")
+	assert.Equal(t, 0, audit.Len())
+	assert.Contains(t, main.String(), "back to main")
+}
+
+type failingFormatter struct{}
+
+func (failingFormatter) Format(*Entry) ([]byte, error) {
+	return nil, errors.New("cannot format")
+}
+
+func TestFallbackFormatter(t *testing.T) {
+	var buffer bytes.Buffer
+	var handled []error
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = failingFormatter{}
+	logger.SetErrorHandler(func(err error) { handled = append(handled, err) })
+	logger.SetFallbackFormatter(&TextFormatter{DisableColors: true, DisableTimestamp: true})
+
+	logger.WithField("a", 1).Info("test")
+	assert.Equal(t, "level=info msg=test a=1 \n", buffer.String())
+	assert.Empty(t, handled)
+
+	buffer.Reset()
+	logger.SetFallbackFormatter(failingFormatter{})
+	logger.Info("test")
+	assert.Equal(t, 0, buffer.Len())
+	assert.Len(t, handled, 1, "the error should be reported when the fallback fails too")
+}
+
+func TestLoggerWithModule(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	logger.SetModuleLevel("db", DebugLevel)
+
+	logger.WithModule("db").Info("x")
+	assert.Equal(t, "level=info msg=x module=db \n", buffer.String())
+
+	buffer.Reset()
+	logger.WithModule("db").Debug("y")
+	assert.Contains(t, buffer.String(), "module=db", "the module level should apply")
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.SetModuleLevel("db", DebugLevel)
+
+	logger.SetLevel(WarnLevel)
+	assert.Equal(t, WarnLevel, logger.GetLevel())
+
+	logger.Info("default")
+	assert.Equal(t, "", buffer.String(), "info should be suppressed on the default module")
+
+	logger.WithModule("db").Info("db")
+	assert.Contains(t, buffer.String(), "msg=db", "the module level should win")
+}
+
+func TestSetModuleLevelStringSetsOwnLevel(t *testing.T) {
+	stdLevel := GetLevel()
+	logger := New()
+	assert.NoError(t, logger.SetModuleLevelString("panic"))
+	assert.Equal(t, PanicLevel, logger.GetLevel())
+	assert.Equal(t, stdLevel, GetLevel(), "the standard logger should not change")
+}
+
+func TestPoolEntries(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	logger.SetPoolEntries(true)
+
+	logger.WithField("a", 1).Info("first")
+	logger.WithField("b", 2).Info("second")
+	assert.Equal(t, "level=info msg=first a=1 \nlevel=info msg=second b=2 \n", buffer.String(),
+		"the fields of a reused entry should not leak")
+
+	var kept []*Entry
+	logger.Hooks.Add(&hookFunc{func(entry *Entry) { kept = append(kept, entry) }})
+	logger.WithField("a", 1).Info("first")
+	logger.WithField("b", 2).Info("second")
+	if assert.Len(t, kept, 2) {
+		assert.Equal(t, Fields{"a": 1}, kept[0].Data, "the entries kept by hooks should not be reused")
+		assert.Equal(t, "first", kept[0].Message)
+	}
+}
+
+func TestPoolEntriesHooksReplacedWhileFiring(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	logger.SetPoolEntries(true)
+
+	// the hook removes itself, the entry it keeps must still not be reused
+	var kept *Entry
+	logger.Hooks.Add(&hookFunc{func(entry *Entry) {
+		kept = entry
+		logger.ReplaceHooks(make(LevelHooks))
+	}})
+	logger.WithField("a", 1).Info("first")
+	logger.WithField("b", 2).Info("second")
+	if assert.NotNil(t, kept) {
+		assert.Equal(t, Fields{"a": 1}, kept.Data)
+		assert.Equal(t, "first", kept.Message)
+	}
+}
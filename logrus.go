@@ -30,11 +30,15 @@ func (level Level) String() string {
 	case PanicLevel:
 		return "panic"
 	}
+	if name, ok := customLevelName(level); ok {
+		return name
+	}
 
 	return "unknown"
 }
 
-// ParseLevel takes a string level and returns the Logrus log level constant.
+// ParseLevel takes a string level and returns the Logrus log level constant,
+// or a level registered with RegisterLevel.
 func ParseLevel(lvl string) (Level, error) {
 	switch strings.ToLower(lvl) {
 	case "panic":
@@ -52,12 +56,16 @@ func ParseLevel(lvl string) (Level, error) {
 	case "trace":
 		return TraceLevel, nil
 	}
+	if level, ok := lookupCustomLevel(strings.ToLower(lvl)); ok {
+		return level, nil
+	}
 
 	var l Level
 	return l, fmt.Errorf("not a valid logrus Level: %q", lvl)
 }
 
-// A constant exposing all logging levels
+// A constant exposing all logging levels, including the ones registered with
+// RegisterLevel
 var AllLevels = []Level{
 	PanicLevel,
 	FatalLevel,
@@ -69,12 +77,13 @@ var AllLevels = []Level{
 }
 
 // These are the different logging levels. You can set the logging level to log
-// on your instance of logger, obtained with `logrus.New()`.
+// on your instance of logger, obtained with `logrus.New()`. Custom levels can
+// be registered in between, see RegisterLevel.
 const (
 	// PanicLevel level, highest level of severity. Logs and then calls panic with the
 	// message passed to Debug, Info, ...
 	PanicLevel Level = iota
-	// FatalLevel level. Logs and then calls `os.Exit(1)`. It will exit even if the
+	// FatalLevel level. Logs and then calls `os.Exit(1)`, see SetFatalExitCode. It will exit even if the
 	// logging level is set to Panic.
 	FatalLevel
 	// ErrorLevel level. Logs. Used for errors that should definitely be noted.
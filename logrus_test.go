@@ -3,6 +3,9 @@ package logrus
 import (
 	"bytes"
 	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -393,3 +396,121 @@ func TestEntryWriter(t *testing.T) {
 	assert.Equal(t, fields["foo"], "bar")
 	assert.Equal(t, fields["level"], "warning")
 }
+
+func TestNilOutWritesToStderr(t *testing.T) {
+	stderr := os.Stderr
+	defer func() {
+		os.Stderr = stderr
+	}()
+	f, err := ioutil.TempFile("", "logrus-stderr")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	os.Stderr = f
+
+	logger := &Logger{
+		Formatter: new(JSONFormatter),
+		Hooks:     make(LevelHooks),
+		Level:     InfoLevel,
+	}
+	assert.NotPanics(t, func() {
+		logger.WithField("foo", "bar").Info("test")
+	})
+
+	b, err := ioutil.ReadFile(f.Name())
+	assert.NoError(t, err)
+	var fields Fields
+	assert.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, "test", fields["msg"])
+	assert.Equal(t, "bar", fields["foo"])
+}
+
+func TestMaxDumpDepth(t *testing.T) {
+	var nested interface{} = "deepest"
+	for i := 0; i < 10; i++ {
+		nested = map[string]interface{}{"child": nested}
+	}
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Info(nested)
+	}, func(fields Fields) {
+		assert.Contains(t, fields["msg"], "deepest")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetMaxDumpDepth(3)
+		log.Info(nested)
+	}, func(fields Fields) {
+		assert.NotContains(t, fields["msg"], "deepest", "values beyond the depth should not be dumped")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetMaxDumpDepth(3)
+		log.Infoln("nested", nested)
+	}, func(fields Fields) {
+		assert.NotContains(t, fields["msg"], "deepest")
+	})
+}
+
+type dumpCounter struct {
+	dumped int
+}
+
+func (c *dumpCounter) String() string {
+	c.dumped++
+	return "dumped"
+}
+
+func TestDisabledLevelDoesNotDump(t *testing.T) {
+	var buffer bytes.Buffer
+	counter := &dumpCounter{}
+	logger := New()
+	logger.Out = &buffer
+
+	logger.Debug(counter)
+	logger.Debugf("%v", counter)
+	logger.Debugln(counter)
+	logger.WithField("a", 1).Trace(counter)
+	logger.NewModule("db").Debug(counter)
+	assert.Equal(t, 0, counter.dumped)
+	assert.Equal(t, 0, buffer.Len())
+
+	assert.Equal(t, InfoLevel, GetLevel())
+	Trace(counter)
+	Debug(counter)
+	Debugf("%v", counter)
+	Traceln(counter)
+	assert.Equal(t, 0, counter.dumped)
+}
+
+func TestPackageLevelFunctions(t *testing.T) {
+	var buffer bytes.Buffer
+	var fields Fields
+	defer func(out io.Writer, formatter Formatter) {
+		SetOutput(out)
+		SetFormatter(formatter)
+	}(std.Out, std.Formatter)
+	SetOutput(&buffer)
+	SetFormatter(new(JSONFormatter))
+
+	Info("test")
+	err := json.Unmarshal(buffer.Bytes(), &fields)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", fields["msg"])
+	assert.Equal(t, "info", fields["level"])
+
+	buffer.Reset()
+	WithModule("db").With("a", 1, "b", 2).Warnf("test %d", 1)
+	err = json.Unmarshal(buffer.Bytes(), &fields)
+	assert.Nil(t, err)
+	assert.Equal(t, "db", fields["module"])
+	assert.Equal(t, 1.0, fields["a"])
+	assert.Equal(t, 2.0, fields["b"])
+	assert.Equal(t, "test 1", fields["msg"])
+
+	buffer.Reset()
+	WithStack().Error("test")
+	err = json.Unmarshal(buffer.Bytes(), &fields)
+	assert.Nil(t, err)
+	assert.Contains(t, fields["stacktrace"], "TestPackageLevelFunctions")
+}
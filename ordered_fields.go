@@ -0,0 +1,49 @@
+package logrus
+
+// KeyValue is a field of OrderedFields.
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedFields are fields the formatters output in the order they are given,
+// instead of alphabetically, see WithOrderedFields.
+type OrderedFields []KeyValue
+
+// Add fields to the Entry which are output in the order they were added, by
+// this call and the previous ones on the Entry, ahead of the fields added by
+// WithFields and after the logger's FieldOrder:
+//
+//    log.WithOrderedFields(logrus.OrderedFields{{"step", 1}, {"action", "resize"}})
+//
+// A key added again keeps its first position.
+func (entry *Entry) WithOrderedFields(fields OrderedFields) *Entry {
+	if entry.noop {
+		return entry
+	}
+	data := make(Fields, len(fields))
+	for _, field := range fields {
+		data[field.Key] = field.Value
+	}
+	derived := entry.WithFields(data)
+
+	// copy, the slice may be shared with the entries derived from this one
+	order := make([]string, len(entry.keyOrder), len(entry.keyOrder)+len(fields))
+	copy(order, entry.keyOrder)
+	for _, field := range fields {
+		if !containsKey(order, field.Key) {
+			order = append(order, field.Key)
+		}
+	}
+	derived.keyOrder = order
+	return derived
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package logrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOrderedFields(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+
+	base := logger.WithField("a", 0).WithOrderedFields(OrderedFields{{"zeta", 1}, {"beta", 2}})
+	base.WithOrderedFields(OrderedFields{{"alpha", 3}, {"zeta", 4}}).Info("test")
+	assert.Equal(t, "level=info msg=test zeta=4 beta=2 alpha=3 a=0 \n", buffer.String())
+
+	buffer.Reset()
+	base.Info("base")
+	assert.Equal(t, "level=info msg=base zeta=1 beta=2 a=0 \n", buffer.String(), "derived entries should not change the order of their base")
+}
+
+func TestWithOrderedFieldsJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &JSONFormatter{DisableTimestamp: true}
+	logger.FieldOrder = []string{"request_id"}
+
+	logger.WithField("request_id", "42").WithOrderedFields(OrderedFields{{"step", 2}, {"action", "resize"}}).Info("test")
+	assert.Equal(t, `{"request_id":"42","step":2,"action":"resize","level":"info","msg":"test"}`+"\n", buffer.String())
+}
@@ -3,7 +3,6 @@ package logrus
 import (
 	"bytes"
 	"sort"
-	"strings"
 	"sync"
 	"time"
 
@@ -112,7 +111,7 @@ func (f *PrettyTextFormatter) Format(entry *Entry) ([]byte, error) {
 
 func (f *PrettyTextFormatter) printColored(b *bytes.Buffer, entry *Entry, keys []string, timestampFormat string) {
 	var levelColor int
-	switch entry.Level {
+	switch standardLevel(entry.Level) {
 	case TraceLevel:
 		levelColor = magenta
 	case DebugLevel:
@@ -125,7 +124,7 @@ func (f *PrettyTextFormatter) printColored(b *bytes.Buffer, entry *Entry, keys [
 		levelColor = blue
 	}
 
-	levelText := strings.ToUpper(entry.Level.String())[0:4]
+	levelText := levelText(entry.Level)
 
 	if f.DisableTimestamp {
 		fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m %-44s ", levelColor, levelText, entry.Message)
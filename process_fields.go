@@ -0,0 +1,72 @@
+package logrus
+
+import (
+	"os"
+	"sync"
+)
+
+// Defines the keys used for the process fields, see SetProcessFields.
+var HostnameKey = "hostname"
+var PIDKey = "pid"
+var VersionKey = "version"
+
+var (
+	processOnce     sync.Once
+	processHostname string
+	processPID      int
+)
+
+// SetProcessFields enables or disables adding the hostname and the pid of the
+// process, and the version set by SetVersion, to every entry. They are looked
+// up once, and fields set on the entry with the same keys win.
+func (logger *Logger) SetProcessFields(enable bool) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.ProcessFields = enable
+}
+
+// SetVersion sets the version of the application added to the entries when
+// the process fields are enabled.
+func (logger *Logger) SetVersion(version string) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.Version = version
+}
+
+// SetFieldsFromEnv adds fields read from environment variables to every entry,
+// e.g. `{"region": "AWS_REGION"}` adds the region the container runs in under
+// the key region. The map goes from the field names to the variable names.
+// The variables are read once, by this call, the ones which are unset or empty
+// are skipped. Like the process fields, fields set on the entry with the same
+// keys win. It replaces the fields of a previous call, passing nil removes
+// them.
+func (logger *Logger) SetFieldsFromEnv(vars map[string]string) {
+	var fields Fields
+	for key, name := range vars {
+		if value := os.Getenv(name); value != "" {
+			if fields == nil {
+				fields = Fields{}
+			}
+			fields[key] = value
+		}
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.EnvFields = fields
+}
+
+// processFields returns the entry's data with the process fields added.
+func (entry *Entry) processFields() Fields {
+	processOnce.Do(func() {
+		processHostname, _ = os.Hostname()
+		processPID = os.Getpid()
+	})
+	fields := Fields{
+		HostnameKey: processHostname,
+		PIDKey:      processPID,
+	}
+	if entry.Logger.Version != "" {
+		fields[VersionKey] = entry.Logger.Version
+	}
+	return entry.fieldsIfAbsent(fields)
+}
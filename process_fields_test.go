@@ -0,0 +1,76 @@
+package logrus
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessFields(t *testing.T) {
+	hostname, _ := os.Hostname()
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetProcessFields(true)
+		log.SetVersion("1.2.3")
+		log.Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, hostname, fields["hostname"])
+		assert.Equal(t, float64(os.Getpid()), fields["pid"])
+		assert.Equal(t, "1.2.3", fields["version"])
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetProcessFields(true)
+		log.Info("test")
+	}, func(fields Fields) {
+		_, ok := fields["version"]
+		assert.False(t, ok, "no version should be added when none is set")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.Info("test")
+	}, func(fields Fields) {
+		_, ok := fields["pid"]
+		assert.False(t, ok, "process fields should be disabled by default")
+	})
+}
+
+func TestProcessFieldsUserFieldsWin(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetProcessFields(true)
+		log.SetVersion("1.2.3")
+		log.WithFields(Fields{"hostname": "walrus", "version": "dev"}).Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "walrus", fields["hostname"])
+		assert.Equal(t, "dev", fields["version"])
+	})
+}
+
+func TestFieldsFromEnv(t *testing.T) {
+	os.Setenv("LOGRUS_TEST_REGION", "eu-west-1")
+	os.Setenv("LOGRUS_TEST_EMPTY", "")
+	defer os.Unsetenv("LOGRUS_TEST_REGION")
+	defer os.Unsetenv("LOGRUS_TEST_EMPTY")
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetFieldsFromEnv(map[string]string{
+			"region":  "LOGRUS_TEST_REGION",
+			"empty":   "LOGRUS_TEST_EMPTY",
+			"missing": "LOGRUS_TEST_MISSING",
+		})
+		os.Setenv("LOGRUS_TEST_REGION", "changed")
+		log.WithField("service", "api").Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "eu-west-1", fields["region"], "the variables should be read once")
+		assert.Equal(t, "api", fields["service"])
+		assert.NotContains(t, fields, "empty")
+		assert.NotContains(t, fields, "missing")
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.SetFieldsFromEnv(map[string]string{"region": "LOGRUS_TEST_REGION"})
+		log.WithField("region", "us-east-1").Info("test")
+	}, func(fields Fields) {
+		assert.Equal(t, "us-east-1", fields["region"], "the entry fields should win")
+	})
+}
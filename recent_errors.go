@@ -0,0 +1,76 @@
+package logrus
+
+import "sync"
+
+// RecentErrorsHook records the latest entry logged at ErrorLevel or a more
+// severe level for each module, e.g. to report recent errors from a health
+// check endpoint without scraping the logs. Install it with
+// `logger.Hooks.Add(NewRecentErrorsHook())` and read it with
+// Logger.RecentErrors.
+type RecentErrorsHook struct {
+	mu      sync.RWMutex
+	entries map[string]*Entry
+}
+
+func NewRecentErrorsHook() *RecentErrorsHook {
+	return &RecentErrorsHook{entries: make(map[string]*Entry)}
+}
+
+func (hook *RecentErrorsHook) Levels() []Level {
+	return []Level{PanicLevel, FatalLevel, ErrorLevel}
+}
+
+// Fire records a copy of the entry, replacing the previous one of its module.
+func (hook *RecentErrorsHook) Fire(e *Entry) error {
+	entry := *e
+	entry.Buffer = nil
+	entry.Data = entry.fieldsWith(nil)
+
+	module := entry.ModuleName()
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	hook.entries[module] = &entry
+	return nil
+}
+
+// Entries returns the latest error entry for each module.
+func (hook *RecentErrorsHook) Entries() map[string]*Entry {
+	hook.mu.RLock()
+	defer hook.mu.RUnlock()
+	entries := make(map[string]*Entry, len(hook.entries))
+	for module, entry := range hook.entries {
+		entries[module] = entry
+	}
+	return entries
+}
+
+// RecentErrors returns the latest error entry for each module, as recorded by
+// the RecentErrorsHook of the logger, possibly wrapped in a PriorityHook or a
+// LevelHook. It returns nil when no such hook is installed.
+func (logger *Logger) RecentErrors() map[string]*Entry {
+	logger.mu.Lock()
+	hooks := logger.uniqueHooks()
+	logger.mu.Unlock()
+	for _, hook := range hooks {
+		if recent, ok := unwrapHook(hook).(*RecentErrorsHook); ok {
+			return recent.Entries()
+		}
+	}
+	return nil
+}
+
+// unwrapHook returns the hook wrapped by the PriorityHook and LevelHook
+// wrappers.
+func unwrapHook(hook Hook) Hook {
+	for {
+		switch wrapper := hook.(type) {
+		case *PriorityHook:
+			hook = wrapper.Hook
+		case *LevelHook:
+			hook = wrapper.Hook
+		default:
+			return hook
+		}
+	}
+}
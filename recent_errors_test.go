@@ -0,0 +1,62 @@
+package logrus
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecentErrors(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	assert.Nil(t, logger.RecentErrors())
+
+	logger.Hooks.Add(NewRecentErrorsHook())
+	db := logger.NewModule("db")
+	db.Error("first")
+	db.WithField("query", "select").Error("second")
+	db.Warn("not an error")
+	logger.NewModule("http").Error("timeout")
+	logger.Error("unnamed")
+
+	recent := logger.RecentErrors()
+	assert.Len(t, recent, 3)
+	assert.Equal(t, "second", recent["db"].Message)
+	assert.Equal(t, "select", recent["db"].Data["query"])
+	assert.Equal(t, ErrorLevel, recent["db"].Level)
+	assert.False(t, recent["db"].Time.IsZero())
+	assert.Equal(t, "timeout", recent["http"].Message)
+	assert.Equal(t, "unnamed", recent[DefaultModuleName].Message)
+}
+
+func TestRecentErrorsWrappedHook(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	logger.Hooks.Add(NewPriorityHook(NewLevelHook(NewRecentErrorsHook(), ErrorLevel), 1))
+	logger.Error("wrapped")
+
+	recent := logger.RecentErrors()
+	if assert.Len(t, recent, 1) {
+		assert.Equal(t, "wrapped", recent[DefaultModuleName].Message)
+	}
+}
+
+func TestRecentErrorsWhileReplacingHooks(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			hooks := make(LevelHooks)
+			hooks.Add(NewRecentErrorsHook())
+			logger.ReplaceHooks(hooks)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		logger.RecentErrors()
+		logger.Flush()
+	}
+	<-done
+}
@@ -0,0 +1,49 @@
+package logrus
+
+import (
+	"fmt"
+
+	"github.com/yyscamper/errors"
+)
+
+// Defines the key of the value of the panics logged by Recover.
+var PanicKey = "panic"
+
+// Recover recovers from a panic and logs it as an error, with the panic value
+// under PanicKey and the stacktrace of the panic:
+//
+//    defer log.WithModule("worker").Recover()
+//
+// As it calls the builtin recover, it only works when deferred directly, as
+// above. Called in any other way, e.g. from a deferred closure, it recovers
+// nothing and the panic goes on. A panic is never swallowed unlogged: on an
+// entry not logging errors, e.g. of If(false) or of a module above
+// ErrorLevel, it panics again with the recovered value.
+func (entry *Entry) Recover() {
+	if r := recover(); r != nil {
+		if !entry.logRecovered(r) {
+			panic(r)
+		}
+	}
+}
+
+// RecoverWith is like Recover and panics again with the recovered value when
+// rethrow is set, so the panic is logged but still crashes the program or
+// reaches an outer handler. It must be deferred directly too.
+func (entry *Entry) RecoverWith(rethrow bool) {
+	if r := recover(); r != nil {
+		if !entry.logRecovered(r) || rethrow {
+			panic(r)
+		}
+	}
+}
+
+// logRecovered logs the recovered value and reports whether the entry logs
+// errors at all.
+func (entry *Entry) logRecovered(r interface{}) bool {
+	if !entry.matchLevel(ErrorLevel) {
+		return false
+	}
+	entry.WithField(PanicKey, r).withStack(errors.Stack(2)).Error(fmt.Sprintf("recovered from panic: %v", r))
+	return true
+}
@@ -0,0 +1,59 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		func() {
+			defer log.NewModule("worker").Recover()
+			panic("boom")
+		}()
+	}, func(fields Fields) {
+		assert.Equal(t, "error", fields["level"])
+		assert.Equal(t, "recovered from panic: boom", fields["msg"])
+		assert.Equal(t, "boom", fields[PanicKey])
+		assert.Equal(t, "worker", fields[ModuleNameKey])
+		assert.NotEmpty(t, fields[StacktraceKey])
+	})
+}
+
+func TestRecoverWithRethrow(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		assert.PanicsWithValue(t, "boom", func() {
+			defer log.WithField("a", 1).RecoverWith(true)
+			panic("boom")
+		})
+	}, func(fields Fields) {
+		assert.Equal(t, "boom", fields[PanicKey])
+		assert.Equal(t, float64(1), fields["a"])
+	})
+}
+
+func TestRecoverWithoutPanic(t *testing.T) {
+	logger := New()
+	logger.Hooks.Add(&hookFunc{func(*Entry) { t.Error("nothing should be logged") }})
+	func() {
+		defer logger.WithField("a", 1).RecoverWith(false)
+	}()
+}
+
+func TestRecoverNotLoggedPanicsAgain(t *testing.T) {
+	logger := New()
+	logger.SetModuleLevel("quiet", FatalLevel)
+	assert.PanicsWithValue(t, "boom", func() {
+		defer logger.NewModule("quiet").Recover()
+		panic("boom")
+	})
+	assert.PanicsWithValue(t, "boom", func() {
+		defer logger.If(false).Recover()
+		panic("boom")
+	})
+	assert.PanicsWithValue(t, "boom", func() {
+		defer logger.If(false).RecoverWith(false)
+		panic("boom")
+	})
+}
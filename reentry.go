@@ -0,0 +1,201 @@
+package logrus
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// nestedFormatter formats the entries logged from inside the emit of another
+// entry, the logger's formatter may be the one logging.
+var nestedFormatter = &TextFormatter{DisableColors: true}
+
+// A callGuard records the goroutines calling the hooks, or the outputs, of the
+// loggers, to tell a hook or output logging back into its logger from the
+// other goroutines logging at the same time.
+//
+// Nothing is looked up while no call is in progress on a logger. Looking up
+// the goroutine ID is not free, so the calls of the logger that started them
+// while no other logger was in one, the common case, are only counted in
+// anonymous. Every call on the stack of a goroutine that isn't recorded by
+// goroutine is then one of that logger's.
+type callGuard struct {
+	// the ID of the logger, shifted left 32 bits, and the number of its calls
+	// not recorded by goroutine, first to be 64-bit aligned
+	anonymous uint64
+	// number of the calls recorded in goroutines
+	recorded int32
+	mu       sync.Mutex
+	// the loggers the calls on each goroutine are made for, in call order
+	goroutines map[uint64][]*Logger
+	// the names of the functions making the calls in the stacktraces
+	frames []string
+}
+
+const anonymousCalls = 1<<32 - 1
+
+var (
+	// the guards of the hooks and of the outputs, the sinks and event sinks
+	// included
+	hookGuard = &callGuard{goroutines: make(map[uint64][]*Logger)}
+	outGuard  = &callGuard{goroutines: make(map[uint64][]*Logger)}
+
+	lastLoggerID uint32
+)
+
+func init() {
+	hookGuard.frames = funcNames(callHooks)
+	outGuard.frames = funcNames((*Logger).writeOut, (*Logger).callEventSink)
+}
+
+func funcNames(funcs ...interface{}) []string {
+	names := make([]string, len(funcs))
+	for i, f := range funcs {
+		names[i] = runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	}
+	return names
+}
+
+// guardID returns the ID identifying the logger in the anonymous calls.
+func (logger *Logger) guardID() uint64 {
+	if id := atomic.LoadUint32(&logger.id); id != 0 {
+		return uint64(id)
+	}
+	atomic.CompareAndSwapUint32(&logger.id, 0, atomic.AddUint32(&lastLoggerID, 1))
+	return uint64(atomic.LoadUint32(&logger.id))
+}
+
+// start records a call made for the logger, counted in calls, on this
+// goroutine. It returns the goroutine if the call is recorded in goroutines,
+// to pass to end.
+func (guard *callGuard) start(logger *Logger, calls *int32) (uint64, bool) {
+	atomic.AddInt32(calls, 1)
+	id := logger.guardID()
+	for {
+		state := atomic.LoadUint64(&guard.anonymous)
+		n := state & anonymousCalls
+		if n != 0 && state>>32 != id {
+			break
+		}
+		if atomic.CompareAndSwapUint64(&guard.anonymous, state, id<<32|(n+1)) {
+			return 0, false
+		}
+	}
+	gid := goroutineID()
+	guard.mu.Lock()
+	guard.goroutines[gid] = append(guard.goroutines[gid], logger)
+	atomic.AddInt32(&guard.recorded, 1)
+	guard.mu.Unlock()
+	return gid, true
+}
+
+func (guard *callGuard) end(calls *int32, gid uint64, recorded bool) {
+	if !recorded {
+		atomic.AddUint64(&guard.anonymous, ^uint64(0))
+	} else {
+		guard.mu.Lock()
+		// the calls of a goroutine end in the reverse order they started
+		if loggers := guard.goroutines[gid]; len(loggers) > 1 {
+			guard.goroutines[gid] = loggers[:len(loggers)-1]
+		} else {
+			delete(guard.goroutines, gid)
+		}
+		atomic.AddInt32(&guard.recorded, -1)
+		guard.mu.Unlock()
+	}
+	atomic.AddInt32(calls, -1)
+}
+
+// nested reports whether the caller is inside one of the calls made for the
+// logger, counted in calls, on this goroutine.
+func (guard *callGuard) nested(logger *Logger, calls *int32) bool {
+	if atomic.LoadInt32(calls) == 0 {
+		return false
+	}
+	depth := guard.depth()
+	if depth == 0 {
+		return false
+	}
+	state := atomic.LoadUint64(&guard.anonymous)
+	anonymous := state&anonymousCalls != 0 && state>>32 == logger.guardID()
+	if atomic.LoadInt32(&guard.recorded) == 0 {
+		return anonymous
+	}
+	gid := goroutineID()
+	guard.mu.Lock()
+	loggers := guard.goroutines[gid]
+	guard.mu.Unlock()
+	for _, l := range loggers {
+		if l == logger {
+			return true
+		}
+	}
+	// the calls on the stack the goroutine's records don't account for are
+	// anonymous ones
+	return anonymous && depth > len(loggers)
+}
+
+// depth returns the number of calls guarded by the guard on the stack of the
+// caller of its caller.
+func (guard *callGuard) depth() int {
+	var pcs [64]uintptr
+	// skip runtime.Callers, depth and its caller
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		for _, name := range guard.frames {
+			if frame.Function == name {
+				depth++
+			}
+		}
+		if !more {
+			return depth
+		}
+	}
+}
+
+// nestedEmit reports whether the caller of emit is a hook or an output of the
+// logger logging through it on this goroutine. Other goroutines logging at
+// the same time, or other loggers' hooks and outputs, don't count.
+func (logger *Logger) nestedEmit() bool {
+	return hookGuard.nested(logger, &logger.hookCalls) || outGuard.nested(logger, &logger.outCalls)
+}
+
+// callHooks fires the hooks for the entry.
+func callHooks(hooks []Hook, entry *Entry) error {
+	logger := entry.Logger
+	gid, recorded := hookGuard.start(logger, &logger.hookCalls)
+	defer hookGuard.end(&logger.hookCalls, gid, recorded)
+	return fireHooks(hooks, entry)
+}
+
+// writeOut writes to an output of the logger, with the logger locked.
+func (logger *Logger) writeOut(out io.Writer, b []byte) (int, error) {
+	gid, recorded := outGuard.start(logger, &logger.outCalls)
+	defer outGuard.end(&logger.outCalls, gid, recorded)
+	return out.Write(b)
+}
+
+// callEventSink sends an event to the event sink of the logger, with the
+// logger locked.
+func (logger *Logger) callEventSink(sink func(LogEvent) error, event LogEvent) error {
+	gid, recorded := outGuard.start(logger, &logger.outCalls)
+	defer outGuard.end(&logger.outCalls, gid, recorded)
+	return sink(event)
+}
+
+// emitNested writes an entry logged from inside the emit of another entry.
+// Firing the hooks again could recurse forever and the logger's output may be
+// locked by the outer entry, so the entry is only formatted as text and
+// written to stderr.
+func (entry *Entry) emitNested() {
+	serialized, err := nestedFormatter.Format(entry)
+	if err == nil {
+		os.Stderr.Write(serialized)
+	}
+}
@@ -0,0 +1,109 @@
+package logrus
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loggingWriter struct {
+	bytes.Buffer
+	logger *Logger
+}
+
+func (w *loggingWriter) Write(p []byte) (int, error) {
+	w.logger.Info("writing")
+	return w.Buffer.Write(p)
+}
+
+func assertReturns(t *testing.T, f func()) {
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging deadlocked")
+	}
+}
+
+func TestReentrantHook(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	fired := 0
+	logger.Hooks.Add(&hookFunc{func(*Entry) {
+		fired++
+		logger.Info("from hook")
+	}})
+
+	assertReturns(t, func() { logger.Info("test") })
+	assert.Equal(t, 1, fired, "the nested entry should not fire the hooks")
+	assert.Contains(t, buffer.String(), "msg=test")
+	assert.NotContains(t, buffer.String(), "from hook")
+}
+
+func TestHookLoggingToBusyLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	busy := New()
+	busy.Out = &buffer
+	started, release := make(chan struct{}), make(chan struct{})
+	busy.Hooks.Add(&hookFunc{func(entry *Entry) {
+		if entry.Message == "busy" {
+			close(started)
+			<-release
+		}
+	}})
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	logger.Hooks.Add(&hookFunc{func(*Entry) {
+		busy.Info("from hook")
+	}})
+
+	done := make(chan struct{})
+	go func() {
+		busy.Info("busy")
+		close(done)
+	}()
+	<-started
+	assertReturns(t, func() { logger.Info("test") })
+	close(release)
+	<-done
+	assert.Contains(t, buffer.String(), `msg="from hook"`, "another goroutine emitting on the logger should not nest the entry")
+	assert.Contains(t, buffer.String(), "msg=busy")
+}
+
+func TestReentrantOutput(t *testing.T) {
+	logger := New()
+	out := &loggingWriter{logger: logger}
+	logger.Out = out
+
+	assertReturns(t, func() { logger.Info("test") })
+	assert.Contains(t, out.String(), "msg=test")
+	assert.NotContains(t, out.String(), "writing")
+}
+
+func TestConcurrentEntriesAreNotNested(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &JSONFormatter{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				logger.Info("test")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 400, bytes.Count(buffer.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,197 @@
+package logrus
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var errRotatingFileWriterClosed = errors.New("logrus: write to closed RotatingFileWriter")
+
+// RotatingFileWriter is an io.WriteCloser writing the log entries to a file
+// and rotating it once it would grow past MaxBytes. Set it as `Logger.Out`:
+//
+//    w, err := logrus.NewRotatingFileWriter("app.log", 10<<20, 5)
+//    if err != nil {
+//      ...
+//    }
+//    logger.Out = w
+//
+// On rotation the current file becomes "app.log.1", the previous
+// "app.log.1" becomes "app.log.2" and so on, at most MaxBackups backups are
+// kept and the oldest is removed. With Compress the backups are gzipped and
+// named "app.log.1.gz" and so on. An entry is never split across files.
+//
+// The writer has its own lock, so it can be shared by several loggers.
+type RotatingFileWriter struct {
+	// Filename is the path of the file being written.
+	Filename string
+	// MaxBytes is the size after which the file is rotated, the file is never
+	// rotated if it is <= 0.
+	MaxBytes int64
+	// MaxBackups is the number of rotated files kept, none are kept if it is
+	// <= 0.
+	MaxBackups int
+	// Compress gzips the rotated files.
+	Compress bool
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// NewRotatingFileWriter opens filename for appending, creating it if needed,
+// and returns a RotatingFileWriter rotating it after maxBytes and keeping
+// maxBackups rotated files.
+func NewRotatingFileWriter(filename string, maxBytes int64, maxBackups int) (*RotatingFileWriter, error) {
+	w := &RotatingFileWriter{
+		Filename:   filename,
+		MaxBytes:   maxBytes,
+		MaxBackups: maxBackups,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.Filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errRotatingFileWriterClosed
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.MaxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.MaxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the file regardless of its size.
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return errRotatingFileWriterClosed
+	}
+	return w.rotate()
+}
+
+func (w *RotatingFileWriter) rotate() error {
+	if w.file != nil {
+		err := w.file.Close()
+		w.file = nil
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.MaxBackups <= 0 {
+		if err := os.Remove(w.Filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return w.open()
+	}
+
+	if err := os.Remove(w.backupName(w.MaxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := w.MaxBackups - 1; i >= 1; i-- {
+		err := os.Rename(w.backupName(i), w.backupName(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if w.Compress {
+		if err := compressFile(w.Filename, w.backupName(1)); err != nil {
+			return err
+		}
+	} else if err := os.Rename(w.Filename, w.backupName(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return w.open()
+}
+
+// backupName returns the name of the i-th most recent rotated file.
+func (w *RotatingFileWriter) backupName(i int) string {
+	name := fmt.Sprintf("%s.%d", w.Filename, i)
+	if w.Compress {
+		name += ".gz"
+	}
+	return name
+}
+
+// compressFile gzips src into dst and removes src.
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		out.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// Close closes the current file. Writing after Close returns an error.
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
@@ -0,0 +1,113 @@
+package logrus
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRotatingTestLogger(t *testing.T, compress bool) (*Logger, *RotatingFileWriter, string) {
+	dir, err := ioutil.TempDir("", "logrus-rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "test.log")
+	w, err := NewRotatingFileWriter(filename, 100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Compress = compress
+	logger := New()
+	logger.Out = w
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	return logger, w, dir
+}
+
+func rotatingTestLines(from, to int) string {
+	var lines string
+	for i := from; i < to; i++ {
+		lines += fmt.Sprintf("level=info msg=test i=%d pad=aaaaaaaaaa \n", i)
+	}
+	return lines
+}
+
+func TestRotatingFileWriter(t *testing.T) {
+	logger, w, dir := newRotatingTestLogger(t, false)
+	defer os.RemoveAll(dir)
+
+	// each line is 40 bytes, so the file holds two entries and two more
+	// entries rotate it twice
+	for i := 0; i < 5; i++ {
+		logger.WithField("i", i).WithField("pad", "aaaaaaaaaa").Info("test")
+	}
+	assert.NoError(t, w.Close())
+
+	for name, expected := range map[string]string{
+		w.Filename:        rotatingTestLines(4, 5),
+		w.Filename + ".1": rotatingTestLines(2, 4),
+		w.Filename + ".2": rotatingTestLines(0, 2),
+	} {
+		b, err := ioutil.ReadFile(name)
+		if assert.NoError(t, err, "%s should exist", name) {
+			assert.Equal(t, expected, string(b), name)
+		}
+	}
+}
+
+func TestRotatingFileWriterKeepsMaxBackups(t *testing.T) {
+	logger, w, dir := newRotatingTestLogger(t, false)
+	defer os.RemoveAll(dir)
+
+	for i := 0; i < 20; i++ {
+		logger.WithField("i", i).WithField("pad", "aaaaaaaaaa").Info("test")
+	}
+	assert.NoError(t, w.Close())
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3, "the file and two backups should be kept")
+	_, err = os.Stat(w.Filename + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFileWriterCompress(t *testing.T) {
+	logger, w, dir := newRotatingTestLogger(t, true)
+	defer os.RemoveAll(dir)
+
+	for i := 0; i < 5; i++ {
+		logger.WithField("i", i).WithField("pad", "aaaaaaaaaa").Info("test")
+	}
+	assert.NoError(t, w.Close())
+
+	for _, name := range []string{w.Filename + ".1.gz", w.Filename + ".2.gz"} {
+		f, err := os.Open(name)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		r, err := gzip.NewReader(f)
+		if assert.NoError(t, err) {
+			b, err := ioutil.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, strings.Count(string(b), "msg=test"))
+		}
+		f.Close()
+	}
+	_, err := os.Stat(w.Filename + ".1")
+	assert.True(t, os.IsNotExist(err), "the uncompressed backup should be removed")
+}
+
+func TestRotatingFileWriterClosed(t *testing.T) {
+	_, w, dir := newRotatingTestLogger(t, false)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+	_, err := w.Write([]byte("test\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package logrus
+
+import "regexp"
+
+// Defines the text replacing the secrets found by the secret patterns.
+var SecretMask = "[REDACTED]"
+
+// AddSecretPattern adds a pattern of secrets, e.g. API keys or credit card
+// numbers, which are masked with SecretMask in the message and the string
+// fields of every entry, before the hooks are fired and the entry is
+// formatted. It catches secrets logged under an unexpected key name.
+//
+// Every pattern is matched against every string of every logged entry, which
+// is costly for busy loggers, there are no patterns by default.
+func (logger *Logger) AddSecretPattern(pattern *regexp.Regexp) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	// copied, the entries being logged keep reading the previous patterns
+	patterns := make([]*regexp.Regexp, len(logger.SecretPatterns), len(logger.SecretPatterns)+1)
+	copy(patterns, logger.SecretPatterns)
+	logger.SecretPatterns = append(patterns, pattern)
+	logger.secretPatterns.Store(logger.SecretPatterns)
+}
+
+// loadSecretPatterns returns the secret patterns without locking the logger,
+// which may be logging from its output. SecretPatterns set directly, before
+// logging, are used until AddSecretPattern is called.
+func (logger *Logger) loadSecretPatterns() []*regexp.Regexp {
+	if patterns, ok := logger.secretPatterns.Load().([]*regexp.Regexp); ok {
+		return patterns
+	}
+	return logger.SecretPatterns
+}
+
+func maskSecrets(patterns []*regexp.Regexp, s string) string {
+	for _, pattern := range patterns {
+		s = pattern.ReplaceAllLiteralString(s, SecretMask)
+	}
+	return s
+}
+
+// maskedFields returns the entry's data with the secrets the patterns find
+// masked in the string values. The data is only copied when a secret is
+// found.
+func (entry *Entry) maskedFields(patterns []*regexp.Regexp) Fields {
+	data := entry.Data
+	copied := false
+	for k, v := range entry.Data {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if masked := maskSecrets(patterns, s); masked != s {
+			if !copied {
+				data = entry.fieldsWith(nil)
+				copied = true
+			}
+			data[k] = masked
+		}
+	}
+	return data
+}
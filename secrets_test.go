@@ -0,0 +1,54 @@
+package logrus
+
+import (
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretPatterns(t *testing.T) {
+	LogAndAssertJSON(t, func(log *Logger) {
+		log.AddSecretPattern(regexp.MustCompile(`\b\d{4}[- ]?\d{4}[- ]?\d{4}[- ]?\d{4}\b`))
+		log.AddSecretPattern(regexp.MustCompile(`sk_live_[0-9a-zA-Z]+`))
+		log.WithFields(Fields{
+			"card":  "4111-1111-1111-1111",
+			"note":  "retry with key sk_live_abc123 later",
+			"count": 1234567812345678,
+			"id":    "1234",
+		}).Info("charging 4111 1111 1111 1111")
+	}, func(fields Fields) {
+		assert.Equal(t, "charging [REDACTED]", fields["msg"])
+		assert.Equal(t, "[REDACTED]", fields["card"])
+		assert.Equal(t, "retry with key [REDACTED] later", fields["note"])
+		assert.Equal(t, 1234567812345678.0, fields["count"], "only strings are scanned")
+		assert.Equal(t, "1234", fields["id"])
+	})
+}
+
+func TestSecretPatternsDoNotModifyEntry(t *testing.T) {
+	logger := New()
+	logger.Out = &bytes.Buffer{}
+	logger.AddSecretPattern(regexp.MustCompile(`secret`))
+	entry := logger.WithField("password", "secret")
+	entry.Info("test")
+	assert.Equal(t, "secret", entry.Data["password"])
+}
+
+func TestAddSecretPatternWhileLogging(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			logger.AddSecretPattern(regexp.MustCompile(`secret`))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		logger.WithField("key", "secret").Info("secret")
+	}
+	<-done
+}
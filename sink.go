@@ -0,0 +1,73 @@
+package logrus
+
+import (
+	"io"
+	"reflect"
+)
+
+// Sink is an additional output of a logger with its own formatter, see
+// AddSink.
+type Sink struct {
+	Out io.Writer
+	// Formatter of the entries written to Out, the logger's Formatter if nil.
+	Formatter Formatter
+	// Least severe level written to Out.
+	MinLevel Level
+}
+
+// AddSink makes the logger also write the entries at minLevel or a more
+// severe level to w, formatted by f, e.g. text on the console through Out and
+// JSON to a file:
+//
+//    logger.AddSink(file, &logrus.JSONFormatter{}, logrus.InfoLevel)
+//
+// The entries must first pass the logger's level, a sink only restricts them
+// further. An entry is formatted once per formatter, sinks sharing the
+// formatter of another sink or of the logger reuse its output.
+func (logger *Logger) AddSink(w io.Writer, f Formatter, minLevel Level) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.Sinks = append(logger.Sinks, Sink{Out: w, Formatter: f, MinLevel: minLevel})
+}
+
+// writeSinks writes the entry to the sinks of the logger. serialized is the
+// entry formatted by the logger's Formatter, or by its FallbackFormatter if
+// the Formatter failed, if formatted is true.
+func (entry *Entry) writeSinks(sinks []Sink, serialized []byte, formatted bool) {
+	cache := make(map[Formatter][]byte)
+	cacheable := func(f Formatter) bool { return reflect.TypeOf(f).Comparable() }
+	if formatted && cacheable(entry.Logger.Formatter) {
+		cache[entry.Logger.Formatter] = serialized
+	}
+
+	for _, sink := range sinks {
+		if !sink.MinLevel.enables(entry.Level) || sink.Out == nil {
+			continue
+		}
+		formatter := sink.Formatter
+		if formatter == nil {
+			formatter = entry.Logger.Formatter
+		}
+		b, ok := []byte(nil), false
+		if cacheable(formatter) {
+			b, ok = cache[formatter]
+		}
+		if !ok {
+			var err error
+			if b, err = formatter.Format(entry); err != nil {
+				entry.Logger.handleError("Failed to obtain reader, %v\n", err)
+				continue
+			}
+			if cacheable(formatter) {
+				cache[formatter] = b
+			}
+		}
+
+		entry.Logger.mu.Lock()
+		_, err := entry.Logger.writeOut(sink.Out, b)
+		entry.Logger.mu.Unlock()
+		if err != nil {
+			entry.Logger.handleError("Failed to write to log, %v\n", err)
+		}
+	}
+}
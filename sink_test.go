@@ -0,0 +1,102 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type formatCounter struct {
+	Formatter
+	calls int
+}
+
+func (f *formatCounter) Format(entry *Entry) ([]byte, error) {
+	f.calls++
+	return f.Formatter.Format(entry)
+}
+
+func TestAddSink(t *testing.T) {
+	var console, file bytes.Buffer
+	logger := New()
+	logger.Out = &console
+	logger.Formatter = &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	logger.AddSink(&file, &JSONFormatter{DisableTimestamp: true}, InfoLevel)
+
+	logger.WithField("a", 1).Info("test")
+
+	assert.Equal(t, "level=info msg=test a=1 \n", console.String())
+	var fields Fields
+	if assert.NoError(t, json.Unmarshal(file.Bytes(), &fields)) {
+		assert.Equal(t, Fields{"level": "info", "msg": "test", "a": float64(1)}, fields)
+	}
+}
+
+func TestSinkMinLevel(t *testing.T) {
+	var console, errors bytes.Buffer
+	logger := New()
+	logger.Out = &console
+	logger.AddSink(&errors, nil, ErrorLevel)
+
+	logger.Info("info")
+	logger.Error("error")
+
+	assert.Contains(t, console.String(), "msg=info")
+	assert.Contains(t, console.String(), "msg=error")
+	assert.NotContains(t, errors.String(), "msg=info")
+	assert.Contains(t, errors.String(), "msg=error")
+}
+
+func TestSinksShareFormattedEntries(t *testing.T) {
+	var out, first, second bytes.Buffer
+	formatter := &formatCounter{Formatter: &TextFormatter{DisableColors: true, DisableTimestamp: true}}
+	logger := New()
+	logger.Out = &out
+	logger.Formatter = formatter
+	logger.AddSink(&first, nil, InfoLevel)
+	logger.AddSink(&second, formatter, InfoLevel)
+
+	logger.Info("test")
+
+	assert.Equal(t, 1, formatter.calls, "the entry should be formatted once")
+	assert.Equal(t, out.String(), first.String())
+	assert.Equal(t, out.String(), second.String())
+}
+
+func TestSinkReusesFallbackOutput(t *testing.T) {
+	var out, sink bytes.Buffer
+	var handled []error
+	logger := New()
+	logger.Out = &out
+	logger.Formatter = failingFormatter{}
+	logger.SetErrorHandler(func(err error) { handled = append(handled, err) })
+	logger.SetFallbackFormatter(&TextFormatter{DisableColors: true, DisableTimestamp: true})
+	logger.AddSink(&sink, nil, InfoLevel)
+
+	logger.Info("test")
+
+	assert.Equal(t, "level=info msg=test \n", sink.String())
+	assert.Empty(t, handled, "the failing formatter should not be run again for the sink")
+}
+
+func TestAddSinkWhileLogging(t *testing.T) {
+	logger := New()
+	logger.Out = ioutil.Discard
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			logger.Info("test")
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		logger.AddSink(ioutil.Discard, nil, InfoLevel)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,29 @@
+package logrus
+
+import "time"
+
+// Defines the key of the duration, in milliseconds, logged by TraceSpan. It is
+// the default key of the histogram hook.
+var DurationKey = "duration_ms"
+
+var noopSpanEnd = func() {}
+
+// TraceSpan logs "enter" and the name of a function at TraceLevel and returns
+// a function logging "exit" and the name with the time elapsed since, under
+// DurationKey, meant to be deferred:
+//
+//    defer log.WithModule("svc").TraceSpan("doWork")()
+//
+// The times come from the logger's clock, see SetClock. Nothing is logged, or
+// measured, when the entry's module doesn't log at TraceLevel.
+func (entry *Entry) TraceSpan(name string) func() {
+	if !entry.matchLevel(TraceLevel) {
+		return noopSpanEnd
+	}
+	start := entry.Logger.now()
+	entry.log(TraceLevel, "enter "+name)
+	return func() {
+		elapsed := entry.Logger.now().Sub(start)
+		entry.withOne(DurationKey, float64(elapsed)/float64(time.Millisecond)).log(TraceLevel, "exit "+name)
+	}
+}
@@ -0,0 +1,48 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceSpan(t *testing.T) {
+	now := time.Date(2017, 8, 22, 10, 11, 12, 0, time.UTC)
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = new(JSONFormatter)
+	logger.Level = TraceLevel
+	logger.SetClock(func() time.Time { return now })
+
+	func() {
+		defer logger.NewModule("svc").TraceSpan("doWork")()
+		now = now.Add(1500 * time.Microsecond)
+	}()
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	var enter, exit Fields
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &enter))
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &exit))
+
+	assert.Equal(t, "enter doWork", enter["msg"])
+	assert.Equal(t, "trace", enter["level"])
+	assert.NotContains(t, enter, DurationKey)
+	assert.Equal(t, "exit doWork", exit["msg"])
+	assert.Equal(t, "svc", exit[ModuleNameKey])
+	assert.Equal(t, 1.5, exit[DurationKey])
+}
+
+func TestTraceSpanDisabled(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+
+	logger.WithField("a", 1).TraceSpan("doWork")()
+	assert.Equal(t, 0, buffer.Len())
+}
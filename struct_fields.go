@@ -0,0 +1,78 @@
+package logrus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StructTagKey is the struct tag consulted by WithStruct to name fields.
+const StructTagKey = "log"
+
+// Nested structs deeper than this are stored as plain values, which also
+// stops pointer cycles from recursing forever.
+const maxStructDepth = 8
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// Add the exported fields of a struct (or pointer to struct) to the Entry.
+// The key is taken from the `log:"name"` tag, falling back to the field name,
+// and fields tagged `log:"-"` are skipped. Nested structs are flattened with
+// dotted keys, e.g. `db.host`, types implementing fmt.Stringer (such as
+// time.Time) are stored as they are. Nil pointers become nil values and
+// unexported fields are ignored.
+func (entry *Entry) WithStruct(v interface{}) *Entry {
+	fields := Fields{}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		structFields(fields, "", rv, 0)
+	}
+	return entry.WithFields(fields)
+}
+
+func structFields(fields Fields, prefix string, rv reflect.Value, depth int) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		tag := field.Tag.Get(StructTagKey)
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			tag = tag[:idx]
+		}
+		if tag == "-" {
+			continue
+		}
+		if tag != "" {
+			name = tag
+		}
+
+		value := rv.Field(i)
+		for value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Struct && !value.Type().Implements(stringerType) {
+			value = value.Elem()
+		}
+
+		if value.Kind() == reflect.Struct && depth < maxStructDepth && !value.Type().Implements(stringerType) {
+			if field.Anonymous && tag == "" {
+				structFields(fields, prefix, value, depth+1)
+			} else {
+				structFields(fields, prefix+name+".", value, depth+1)
+			}
+			continue
+		}
+		switch value.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			if value.IsNil() {
+				fields[prefix+name] = nil
+				continue
+			}
+		}
+		fields[prefix+name] = value.Interface()
+	}
+}
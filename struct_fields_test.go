@@ -0,0 +1,67 @@
+package logrus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDBConfig struct {
+	Host     string
+	Port     int    `log:"port"`
+	Password string `log:"-"`
+}
+
+type testConfig struct {
+	Name    string `log:"name"`
+	Debug   bool
+	DB      testDBConfig `log:"db"`
+	Replica *testDBConfig
+	Started time.Time `log:"started"`
+	secret  string
+}
+
+func TestWithStruct(t *testing.T) {
+	started := time.Now()
+	cfg := testConfig{
+		Name:    "api",
+		Debug:   true,
+		DB:      testDBConfig{Host: "localhost", Port: 5432, Password: "hunter2"},
+		Started: started,
+		secret:  "hidden",
+	}
+
+	logger := New()
+	data := logger.WithStruct(&cfg).Data
+
+	assert.Equal(t, "api", data["name"])
+	assert.Equal(t, true, data["Debug"])
+	assert.Equal(t, "localhost", data["db.Host"])
+	assert.Equal(t, 5432, data["db.port"])
+	assert.Equal(t, started, data["started"])
+	assert.Nil(t, data["Replica"])
+	_, ok := data["Replica"]
+	assert.True(t, ok, "nil pointer fields should be kept as nil values")
+
+	for _, key := range []string{"db.Password", "db.-", "secret", "DB.Host"} {
+		_, ok := data[key]
+		assert.False(t, ok, "unexpected key %q", key)
+	}
+	assert.Equal(t, 6, len(data))
+}
+
+func TestWithStructNestedPointer(t *testing.T) {
+	cfg := testConfig{Replica: &testDBConfig{Host: "replica", Port: 5433}}
+	data := New().WithStruct(cfg).Data
+
+	assert.Equal(t, "replica", data["Replica.Host"])
+	assert.Equal(t, 5433, data["Replica.port"])
+}
+
+func TestWithStructIgnoresNonStructs(t *testing.T) {
+	entry := New().WithField("foo", "bar")
+
+	assert.Equal(t, Fields{"foo": "bar"}, entry.WithStruct(42).Data)
+	assert.Equal(t, Fields{"foo": "bar"}, entry.WithStruct((*testConfig)(nil)).Data)
+}
@@ -0,0 +1,56 @@
+package logrus
+
+// TeeHook forwards a copy of the entries to another logger, which formats and
+// writes them with its own formatter, output and hooks, e.g. to also send the
+// warnings of a library to the logger of the application using it:
+//
+//    log.Hooks.Add(logrus.NewTeeHook(appLogger, logrus.WarnLevel, logrus.ErrorLevel))
+//
+// The level of the target logger applies too. An entry is never forwarded
+// back to a logger it went through, so teeing loggers to each other doesn't
+// loop.
+type TeeHook struct {
+	Target *Logger
+	levels []Level
+}
+
+// NewTeeHook creates a hook forwarding the entries of the given levels, all
+// the levels if none is given, to target.
+func NewTeeHook(target *Logger, levels ...Level) *TeeHook {
+	if len(levels) == 0 {
+		levels = AllLevels
+	}
+	return &TeeHook{Target: target, levels: levels}
+}
+
+func (hook *TeeHook) Levels() []Level {
+	return hook.levels
+}
+
+func (hook *TeeHook) Fire(entry *Entry) error {
+	target := hook.Target
+	if target == nil || target == entry.Logger {
+		return nil
+	}
+	for _, logger := range entry.teedFrom {
+		if logger == target {
+			return nil
+		}
+	}
+	if !target.level(entry.ModuleName()).enables(entry.Level) {
+		return nil
+	}
+
+	teed := &Entry{
+		Logger:   target,
+		Data:     entry.fieldsWith(nil),
+		Time:     entry.Time,
+		Level:    entry.Level,
+		Message:  entry.Message,
+		Context:  entry.Context,
+		keyOrder: entry.keyOrder,
+		teedFrom: append(entry.teedFrom[:len(entry.teedFrom):len(entry.teedFrom)], entry.Logger),
+	}
+	teed.emitUnguarded()
+	return nil
+}
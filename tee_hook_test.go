@@ -0,0 +1,63 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTeeLogger(formatter Formatter) (*Logger, *bytes.Buffer) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = formatter
+	return logger, &buffer
+}
+
+func TestTeeHook(t *testing.T) {
+	source, sourceOut := newTeeLogger(&TextFormatter{DisableColors: true})
+	target, targetOut := newTeeLogger(new(JSONFormatter))
+	source.Hooks.Add(NewTeeHook(target))
+
+	source.WithField("user", "alice").Info("signed in")
+
+	assert.Contains(t, sourceOut.String(), `msg="signed in"`)
+	assert.Contains(t, sourceOut.String(), "user=alice")
+
+	var fields Fields
+	assert.NoError(t, json.Unmarshal(targetOut.Bytes(), &fields))
+	assert.Equal(t, "signed in", fields["msg"])
+	assert.Equal(t, "info", fields["level"])
+	assert.Equal(t, "alice", fields["user"])
+}
+
+func TestTeeHookLevels(t *testing.T) {
+	source, _ := newTeeLogger(new(JSONFormatter))
+	target, targetOut := newTeeLogger(new(JSONFormatter))
+	source.Hooks.Add(NewTeeHook(target, ErrorLevel))
+
+	source.Info("not forwarded")
+	assert.Equal(t, 0, targetOut.Len())
+	source.Error("forwarded")
+	assert.Contains(t, targetOut.String(), "forwarded")
+
+	targetOut.Reset()
+	target.Level = PanicLevel
+	source.Error("below the target level")
+	assert.Equal(t, 0, targetOut.Len())
+}
+
+func TestTeeHookLoop(t *testing.T) {
+	a, aOut := newTeeLogger(new(JSONFormatter))
+	b, bOut := newTeeLogger(new(JSONFormatter))
+	a.Hooks.Add(NewTeeHook(a))
+	a.Hooks.Add(NewTeeHook(b))
+	b.Hooks.Add(NewTeeHook(a))
+
+	a.Info("once")
+	assert.Equal(t, 1, strings.Count(aOut.String(), "once"))
+	assert.Equal(t, 1, strings.Count(bOut.String(), "once"))
+}
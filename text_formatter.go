@@ -2,8 +2,10 @@ package logrus
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
-	"sort"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +29,38 @@ func init() {
 	baseTimestamp = time.Now()
 }
 
+// ColorScheme sets the ANSI color code used for each level by the
+// TextFormatter, e.g. 36 for cyan. Levels left to 0 use the default color.
+type ColorScheme struct {
+	Panic int
+	Fatal int
+	Error int
+	Warn  int
+	Info  int
+	Debug int
+	Trace int
+}
+
+func (scheme *ColorScheme) color(level Level) int {
+	switch standardLevel(level) {
+	case PanicLevel:
+		return scheme.Panic
+	case FatalLevel:
+		return scheme.Fatal
+	case ErrorLevel:
+		return scheme.Error
+	case WarnLevel:
+		return scheme.Warn
+	case InfoLevel:
+		return scheme.Info
+	case DebugLevel:
+		return scheme.Debug
+	case TraceLevel:
+		return scheme.Trace
+	}
+	return nocolor
+}
+
 type TextFormatter struct {
 	// Set to true to bypass checking for a TTY before outputting colors.
 	ForceColors bool
@@ -42,9 +76,35 @@ type TextFormatter struct {
 	// the time passed since beginning of execution.
 	FullTimestamp bool
 
-	// TimestampFormat to use for display when a full timestamp is printed
+	// TimestampFormat to use for display when a full timestamp is printed,
+	// defaults to DefaultTimestampFormat
 	TimestampFormat string
 
+	// UseEpoch prints the timestamps as nanoseconds since the Unix epoch
+	// instead of formatting them
+	UseEpoch bool
+
+	// RelativeTimestamp prefixes the lines with the time elapsed since the
+	// first entry formatted, e.g. `+0.042s`, instead of the timestamp. The
+	// times are the ones of the entries, see Logger.SetClock, and an entry
+	// created before the first one formatted, e.g. by another goroutine or
+	// with WithTime, gets `+0.000s`.
+	RelativeTimestamp bool
+
+	// Time of the first entry formatted, see RelativeTimestamp
+	start time.Time
+
+	// Aligned prints the lines in columns for reading in a console, e.g.
+	// `INFO    [db  ] message key=val`, without colors: the level is padded to
+	// the longest level name and the module to ModuleWidth. Colored output is
+	// already aligned and ignores it.
+	Aligned bool
+
+	// ModuleWidth is the width the modules are padded to when Aligned is set,
+	// the entries without a module then get an empty column. Modules are not
+	// padded if it is 0.
+	ModuleWidth int
+
 	// The fields are sorted by default for a consistent output. For applications
 	// that log extremely frequently and don't use the JSON formatter this may not
 	// be desired.
@@ -60,6 +120,9 @@ type TextFormatter struct {
 	// Whether the logger's out is to a terminal
 	isTerminal bool
 
+	// Colors of the levels, see SetColorScheme
+	colorScheme *ColorScheme
+
 	sync.Once
 }
 
@@ -70,17 +133,19 @@ func (f *TextFormatter) init(entry *Entry) {
 	if entry.Logger != nil {
 		f.isTerminal = IsTerminal(entry.Logger.Out)
 	}
+	f.start = entry.Time
 }
 
 func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	var b *bytes.Buffer
-	keys := make([]string, 0, len(entry.Data))
-	for k := range entry.Data {
-		keys = append(keys, k)
-	}
-
-	if !f.DisableSorting {
-		sort.Strings(keys)
+	var keys []string
+	if f.DisableSorting {
+		keys = make([]string, 0, len(entry.Data))
+		for k := range entry.Data {
+			keys = append(keys, k)
+		}
+	} else {
+		keys = entry.SortedKeys()
 	}
 	if entry.Buffer != nil {
 		b = entry.Buffer
@@ -90,6 +155,14 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 
 	prefixFieldClashes(entry.Data)
 
+	// a multi-line stacktrace is printed on the lines following the entry
+	stack, _ := entry.Data[StacktraceKey].(string)
+	if strings.Contains(strings.TrimSpace(stack), "\n") {
+		keys = removeKey(keys, StacktraceKey)
+	} else {
+		stack = ""
+	}
+
 	f.Do(func() { f.init(entry) })
 
 	isColored := (f.ForceColors || f.isTerminal) && !f.DisableColors
@@ -100,9 +173,14 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	}
 	if isColored {
 		f.printColored(b, entry, keys, timestampFormat)
+	} else if f.Aligned {
+		f.printAligned(b, entry, keys, timestampFormat)
 	} else {
-		if !f.DisableTimestamp {
-			f.appendKeyValue(b, "time", entry.Time.Format(timestampFormat))
+		if f.RelativeTimestamp && !f.DisableTimestamp {
+			b.WriteString(f.relativeTimestamp(entry))
+			b.WriteByte(' ')
+		} else if !f.DisableTimestamp {
+			f.appendKeyValue(b, "time", f.timestamp(entry, timestampFormat))
 		}
 		f.appendKeyValue(b, "level", entry.Level.String())
 		if entry.Message != "" {
@@ -114,12 +192,61 @@ func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
 	}
 
 	b.WriteByte('\n')
+	if stack != "" {
+		b.WriteString(StacktraceKey)
+		b.WriteString(":\n")
+		for _, line := range strings.Split(strings.TrimSpace(stack), "\n") {
+			b.WriteString("  ")
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
 	return b.Bytes(), nil
 }
 
+func removeKey(keys []string, key string) []string {
+	for i, k := range keys {
+		if k == key {
+			return append(keys[:i:i], keys[i+1:]...)
+		}
+	}
+	return keys
+}
+
+func (f *TextFormatter) timestamp(entry *Entry, timestampFormat string) string {
+	if f.UseEpoch {
+		return strconv.FormatInt(entry.Time.UnixNano(), 10)
+	}
+	return entry.Time.Format(timestampFormat)
+}
+
+func (f *TextFormatter) relativeTimestamp(entry *Entry) string {
+	elapsed := entry.Time.Sub(f.start)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return fmt.Sprintf("+%.3fs", elapsed.Seconds())
+}
+
+// SetColorScheme sets the colors of the levels, which are only used when
+// colors are enabled. Passing nil restores the default colors.
+func (f *TextFormatter) SetColorScheme(scheme *ColorScheme) {
+	f.colorScheme = scheme
+}
+
+// levelText returns the four letters naming the level in colored output,
+// padded for the custom levels with shorter names.
+func levelText(level Level) string {
+	text := strings.ToUpper(level.String())
+	if len(text) < 4 {
+		return fmt.Sprintf("%-4s", text)
+	}
+	return text[0:4]
+}
+
 func (f *TextFormatter) printColored(b *bytes.Buffer, entry *Entry, keys []string, timestampFormat string) {
 	var levelColor int
-	switch entry.Level {
+	switch standardLevel(entry.Level) {
 	case TraceLevel:
 		levelColor = magenta
 	case DebugLevel:
@@ -131,15 +258,20 @@ func (f *TextFormatter) printColored(b *bytes.Buffer, entry *Entry, keys []strin
 	default:
 		levelColor = blue
 	}
+	if f.colorScheme != nil && f.colorScheme.color(entry.Level) != nocolor {
+		levelColor = f.colorScheme.color(entry.Level)
+	}
 
-	levelText := strings.ToUpper(entry.Level.String())[0:4]
+	levelText := levelText(entry.Level)
 
 	if f.DisableTimestamp {
 		fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m %-44s ", levelColor, levelText, entry.Message)
+	} else if f.RelativeTimestamp {
+		fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m[%s] %-44s ", levelColor, levelText, f.relativeTimestamp(entry), entry.Message)
 	} else if !f.FullTimestamp {
 		fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m[%04d] %-44s ", levelColor, levelText, int(entry.Time.Sub(baseTimestamp)/time.Second), entry.Message)
 	} else {
-		fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m[%s] %-44s ", levelColor, levelText, entry.Time.Format(timestampFormat), entry.Message)
+		fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m[%s] %-44s ", levelColor, levelText, f.timestamp(entry, timestampFormat), entry.Message)
 	}
 	for _, k := range keys {
 		v := entry.Data[k]
@@ -148,6 +280,45 @@ func (f *TextFormatter) printColored(b *bytes.Buffer, entry *Entry, keys []strin
 	}
 }
 
+func (f *TextFormatter) printAligned(b *bytes.Buffer, entry *Entry, keys []string, timestampFormat string) {
+	if f.RelativeTimestamp && !f.DisableTimestamp {
+		b.WriteString(f.relativeTimestamp(entry))
+		b.WriteByte(' ')
+	} else if !f.DisableTimestamp {
+		b.WriteString(f.timestamp(entry, timestampFormat))
+		b.WriteByte(' ')
+	}
+	fmt.Fprintf(b, "%-*s ", levelNameWidth(), strings.ToUpper(entry.Level.String()))
+
+	module, hasModule := entry.Data[ModuleNameKey]
+	if hasModule {
+		keys = removeKey(keys, ModuleNameKey)
+		fmt.Fprintf(b, "[%-*s] ", f.ModuleWidth, stringify(module))
+	} else if f.ModuleWidth > 0 {
+		fmt.Fprintf(b, "%*s ", f.ModuleWidth+2, "")
+	}
+
+	b.WriteString(entry.Message)
+	for _, key := range keys {
+		b.WriteByte(' ')
+		b.WriteString(key)
+		b.WriteByte('=')
+		f.appendValue(b, entry.Data[key])
+	}
+}
+
+// levelNameWidth returns the length of the longest level name, including the
+// custom levels.
+func levelNameWidth() int {
+	width := 0
+	for _, level := range AllLevels {
+		if n := len(level.String()); n > width {
+			width = n
+		}
+	}
+	return width
+}
+
 func (f *TextFormatter) needsQuoting(text string) bool {
 	if f.QuoteEmptyFields && len(text) == 0 {
 		return true
@@ -174,19 +345,63 @@ func (f *TextFormatter) appendKeyValue(b *bytes.Buffer, key string, value interf
 func (f *TextFormatter) appendValue(b *bytes.Buffer, value interface{}) {
 	switch value := value.(type) {
 	case string:
-		if !f.needsQuoting(value) {
-			b.WriteString(value)
-		} else {
-			fmt.Fprintf(b, "%s%v%s", f.QuoteCharacter, value, f.QuoteCharacter)
-		}
+		f.appendString(b, value)
 	case error:
-		errmsg := value.Error()
-		if !f.needsQuoting(errmsg) {
-			b.WriteString(errmsg)
-		} else {
-			fmt.Fprintf(b, "%s%v%s", f.QuoteCharacter, errmsg, f.QuoteCharacter)
-		}
+		f.appendString(b, value.Error())
 	default:
+		// maps, slices and structs as compact JSON, so they can be parsed,
+		// quoted like a string. []byte is still printed as numbers, not base64
+		// encoded.
+		if _, ok := value.([]byte); !ok && isComplexValue(value) {
+			if serialized, err := json.Marshal(value); err == nil {
+				f.appendString(b, string(serialized))
+				return
+			}
+		}
 		fmt.Fprint(b, value)
 	}
 }
+
+// appendString writes the string as is, or quoted with its backslashes and
+// quote characters escaped so that it stays a single value.
+func (f *TextFormatter) appendString(b *bytes.Buffer, s string) {
+	if !f.needsQuoting(s) {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(f.QuoteCharacter)
+	quoteEscaper(f.QuoteCharacter).WriteString(b, s)
+	b.WriteString(f.QuoteCharacter)
+}
+
+// the escapers of the quote characters in use, QuoteCharacter may change
+// between two entries
+var quoteEscapers = struct {
+	sync.RWMutex
+	m map[string]*strings.Replacer
+}{m: map[string]*strings.Replacer{`"`: strings.NewReplacer(`\`, `\\`, `"`, `\"`)}}
+
+func quoteEscaper(quote string) *strings.Replacer {
+	quoteEscapers.RLock()
+	escaper, ok := quoteEscapers.m[quote]
+	quoteEscapers.RUnlock()
+	if ok {
+		return escaper
+	}
+	escaper = strings.NewReplacer(`\`, `\\`, quote, `\`+quote)
+	quoteEscapers.Lock()
+	quoteEscapers.m[quote] = escaper
+	quoteEscapers.Unlock()
+	return escaper
+}
+
+func isComplexValue(value interface{}) bool {
+	if _, ok := value.(fmt.Stringer); ok {
+		return false
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return true
+	}
+	return false
+}
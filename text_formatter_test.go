@@ -3,6 +3,8 @@ package logrus
 import (
 	"bytes"
 	"errors"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -83,5 +85,228 @@ func TestDisableTimestampWithColoredOutput(t *testing.T) {
 	}
 }
 
+var levelColorRegexp = regexp.MustCompile("^\x1b\\[(\\d+)m")
+
+func TestColorScheme(t *testing.T) {
+	tf := &TextFormatter{DisableTimestamp: true, ForceColors: true}
+	tf.SetColorScheme(&ColorScheme{Error: 35, Info: 36, Debug: 90})
+
+	expected := map[Level]string{
+		ErrorLevel: "35",
+		InfoLevel:  "36",
+		DebugLevel: "90",
+		// not in the scheme, the defaults are used
+		WarnLevel:  "33",
+		TraceLevel: "35",
+		FatalLevel: "31",
+	}
+	for level, color := range expected {
+		entry := WithField("test", "test")
+		entry.Level = level
+		b, _ := tf.Format(entry)
+		match := levelColorRegexp.FindSubmatch(b)
+		if match == nil {
+			t.Errorf("no color for level %s in %q", level, b)
+		} else if string(match[1]) != color {
+			t.Errorf("expected color %s for level %s, got %s", color, level, match[1])
+		}
+	}
+}
+
+func TestColorSchemeWithoutColors(t *testing.T) {
+	for _, tf := range []*TextFormatter{
+		{DisableTimestamp: true, ForceColors: true, DisableColors: true},
+		// not a terminal
+		{DisableTimestamp: true},
+	} {
+		tf.SetColorScheme(&ColorScheme{Info: 36})
+		logger := New()
+		logger.Out = &bytes.Buffer{}
+		entry := logger.WithField("test", "test")
+		entry.Level = InfoLevel
+		b, _ := tf.Format(entry)
+		if strings.Contains(string(b), "\x1b[") {
+			t.Errorf("colors not expected in %q", b)
+		}
+	}
+}
+
 // TODO add tests for sorting etc., this requires a parser for the text
 // formatter output.
+
+func TestTextFieldOrder(t *testing.T) {
+	logger := New()
+	logger.FieldOrder = []string{"request_id", "module"}
+	tf := &TextFormatter{DisableColors: true, DisableTimestamp: true}
+
+	entry := logger.WithFields(Fields{"b": 2, "a": 1, "module": "db", "request_id": "42"})
+	entry.Level = InfoLevel
+	entry.Message = "test"
+	b, _ := tf.Format(entry)
+	if s := string(b); s != "level=info msg=test request_id=42 module=db a=1 b=2 \n" {
+		t.Errorf("Unexpected field order: %q", s)
+	}
+}
+
+func TestComplexValuesAsJSON(t *testing.T) {
+	tf := &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	entry := WithFields(Fields{
+		"counts": map[string]int{"a": 1, "b": 2},
+		"names":  []string{"walrus", "seal"},
+		"nested": map[string]string{"a": `hello "world"`},
+		"at":     time.Date(2017, 8, 22, 10, 11, 12, 0, time.UTC),
+		"n":      42,
+		"bytes":  []byte("hi"),
+	})
+	entry.Level = InfoLevel
+	entry.Message = "test"
+
+	b, _ := tf.Format(entry)
+	s := string(b)
+	for _, expected := range []string{
+		// quoted so that logfmt parsers can read them back
+		`counts=` + strconv.Quote(`{"a":1,"b":2}`),
+		`names=` + strconv.Quote(`["walrus","seal"]`),
+		`nested=` + strconv.Quote(`{"a":"hello \"world\""}`),
+		`at=2017-08-22 10:11:12 +0000 UTC`,
+		`n=42`,
+		`bytes=[104 105]`,
+	} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("Expected %s in %q", expected, s)
+		}
+	}
+}
+
+func TestQuotedValuesEscaped(t *testing.T) {
+	tf := &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	entry := WithFields(Fields{
+		"plain":  `say "hi" \ bye`,
+		"err":    errors.New(`bad "input"`),
+		"nested": map[string]string{"a": `"b"`},
+	})
+	entry.Level = InfoLevel
+	entry.Message = "test"
+
+	b, _ := tf.Format(entry)
+	s := string(b)
+	for _, expected := range []string{
+		`plain=` + strconv.Quote(`say "hi" \ bye`),
+		`err=` + strconv.Quote(`bad "input"`),
+		`nested=` + strconv.Quote(`{"a":"\"b\""}`),
+	} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("Expected %s in %q", expected, s)
+		}
+	}
+
+	tf.QuoteCharacter = "'"
+	b, _ = tf.Format(WithField("plain", `it's`))
+	if expected := `plain='it\'s'`; !strings.Contains(string(b), expected) {
+		t.Errorf("Expected %s in %q", expected, b)
+	}
+}
+
+func TestTimestampLayouts(t *testing.T) {
+	entry := WithField("a", 1)
+	entry.Level = InfoLevel
+	entry.Message = "test"
+	entry.Time = time.Date(2017, 8, 22, 10, 11, 12, 345, time.UTC)
+
+	for _, test := range []struct {
+		formatter *TextFormatter
+		expected  string
+	}{
+		{&TextFormatter{DisableColors: true}, `time="2017-08-22T10:11:12.000000345Z"`},
+		{&TextFormatter{DisableColors: true, TimestampFormat: time.RFC3339}, `time="2017-08-22T10:11:12Z"`},
+		{&TextFormatter{DisableColors: true, TimestampFormat: "2006/01/02"}, `time="2017/08/22"`},
+		{&TextFormatter{DisableColors: true, UseEpoch: true}, `time=1503396672000000345 `},
+	} {
+		b, _ := test.formatter.Format(entry)
+		if !strings.HasPrefix(string(b), test.expected) {
+			t.Errorf("Expected %s at the start of %q", test.expected, string(b))
+		}
+	}
+}
+
+func TestRelativeTimestamp(t *testing.T) {
+	now := time.Date(2017, 8, 22, 10, 11, 12, 0, time.UTC)
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	logger.Formatter = &TextFormatter{DisableColors: true, RelativeTimestamp: true}
+	logger.SetClock(func() time.Time { return now })
+
+	logger.Info("first")
+	now = now.Add(42 * time.Millisecond)
+	logger.Info("second")
+	now = now.Add(2 * time.Second)
+	logger.Info("third")
+	logger.WithTime(now.Add(-time.Hour)).Info("earlier")
+
+	expected := "+0.000s level=info msg=first \n" +
+		"+0.042s level=info msg=second \n" +
+		"+2.042s level=info msg=third \n" +
+		"+0.000s level=info msg=earlier \n"
+	if buffer.String() != expected {
+		t.Errorf("Unexpected relative timestamps %q", buffer.String())
+	}
+}
+
+func TestMultilineStacktrace(t *testing.T) {
+	tf := &TextFormatter{DisableColors: true, DisableTimestamp: true}
+	entry := WithFields(Fields{
+		"a":           1,
+		StacktraceKey: "goroutine 1 [running]:\nmain.main()\n\tmain.go:10\n",
+		"z":           2,
+	})
+	entry.Level = ErrorLevel
+	entry.Message = "failed"
+
+	b, _ := tf.Format(entry)
+	expected := "level=error msg=failed a=1 z=2 \n" +
+		"stacktrace:\n" +
+		"  goroutine 1 [running]:\n" +
+		"  main.main()\n" +
+		"  \tmain.go:10\n"
+	if string(b) != expected {
+		t.Errorf("Unexpected stacktrace layout %q", string(b))
+	}
+
+	entry = WithField(StacktraceKey, "main.main()")
+	entry.Message = "single"
+	b, _ = tf.Format(entry)
+	if !strings.HasSuffix(string(b), "stacktrace=\"main.main()\" \n") {
+		t.Errorf("A single line stacktrace should stay a field, got %q", string(b))
+	}
+}
+
+func TestAligned(t *testing.T) {
+	tf := &TextFormatter{DisableColors: true, DisableTimestamp: true, Aligned: true, ModuleWidth: 4}
+
+	info := WithFields(Fields{ModuleNameKey: "db", "key": "val"})
+	info.Level = InfoLevel
+	info.Message = "connected"
+	warning := WithField("key", 2)
+	warning.Level = WarnLevel
+	warning.Message = "slow"
+
+	for _, tc := range []struct {
+		entry    *Entry
+		expected string
+	}{
+		{info, "INFO    [db  ] connected key=val\n"},
+		{warning, "WARNING        slow key=2\n"},
+	} {
+		b, _ := tf.Format(tc.entry)
+		if string(b) != tc.expected {
+			t.Errorf("Unexpected aligned line %q, expected %q", string(b), tc.expected)
+		}
+	}
+
+	infoLine, _ := tf.Format(info)
+	warningLine, _ := tf.Format(warning)
+	if strings.Index(string(infoLine), "connected") != strings.Index(string(warningLine), "slow") {
+		t.Errorf("The messages should start at the same column:\n%s%s", infoLine, warningLine)
+	}
+}
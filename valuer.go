@@ -0,0 +1,35 @@
+package logrus
+
+// Valuer is a field value computed when the entry is logged, for values only
+// worth computing when the level of the entry is enabled:
+//
+//    log.WithField("stats", logrus.Valuer(func() interface{} { return cache.Stats() })).Debug("cache")
+//
+// The function is called once per logged entry, before the hooks are fired
+// and the secret patterns applied, and never for entries discarded because of
+// their level. Any `func() interface{}` field value is a Valuer.
+type Valuer func() interface{}
+
+// resolvedValuers returns the entry's data with the Valuer fields replaced by
+// their value. The data is only copied when there are Valuer fields.
+func (entry *Entry) resolvedValuers() Fields {
+	data := entry.Data
+	copied := false
+	for k, v := range entry.Data {
+		var valuer func() interface{}
+		switch v := v.(type) {
+		case Valuer:
+			valuer = v
+		case func() interface{}:
+			valuer = v
+		default:
+			continue
+		}
+		if !copied {
+			data = entry.fieldsWith(nil)
+			copied = true
+		}
+		data[k] = valuer()
+	}
+	return data
+}
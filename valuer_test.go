@@ -0,0 +1,38 @@
+package logrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuer(t *testing.T) {
+	calls := 0
+	stats := Valuer(func() interface{} {
+		calls++
+		return 42
+	})
+
+	LogAndAssertJSON(t, func(log *Logger) {
+		entry := log.WithField("stats", stats)
+		entry.Debug("suppressed")
+		assert.Equal(t, 0, calls, "the valuer should not run for a suppressed entry")
+		entry.Info("emitted")
+	}, func(fields Fields) {
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, float64(42), fields["stats"])
+	})
+}
+
+func TestValuerPlainFunc(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New()
+	logger.Out = &buffer
+	entry := logger.WithField("user", func() interface{} { return "alice" })
+	entry.Info("test")
+
+	assert.Contains(t, buffer.String(), "user=alice")
+	_, ok := entry.Data["user"].(func() interface{})
+	assert.True(t, ok, "the entry should keep the valuer")
+}
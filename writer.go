@@ -6,18 +6,27 @@ import (
 	"runtime"
 )
 
+// Writer returns an io.Writer logging every line written to it at InfoLevel.
+// This makes it possible to hand the logger to code taking an io.Writer, like
+// `http.Server.ErrorLog` through `log.New(logger.Writer(), "", 0)`. The caller
+// must close the writer when done, a trailing line without newline is logged on
+// Close.
 func (logger *Logger) Writer() *io.PipeWriter {
 	return logger.WriterLevel(InfoLevel)
 }
 
+// WriterLevel is like Writer but logs at the given level.
 func (logger *Logger) WriterLevel(level Level) *io.PipeWriter {
 	return NewEntry(logger).WriterLevel(level)
 }
 
+// Writer returns an io.Writer logging every line written to it at InfoLevel
+// with the fields (including the module) of the entry.
 func (entry *Entry) Writer() *io.PipeWriter {
 	return entry.WriterLevel(InfoLevel)
 }
 
+// WriterLevel is like Writer but logs at the given level.
 func (entry *Entry) WriterLevel(level Level) *io.PipeWriter {
 	reader, writer := io.Pipe()
 
@@ -39,7 +48,7 @@ func (entry *Entry) WriterLevel(level Level) *io.PipeWriter {
 	case PanicLevel:
 		printFunc = entry.Panic
 	default:
-		printFunc = entry.Print
+		printFunc = func(args ...interface{}) { entry.Log(level, args...) }
 	}
 
 	go entry.writerScanner(reader, printFunc)
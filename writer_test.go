@@ -0,0 +1,53 @@
+package logrus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readWriterEntry(t *testing.T, cw channelWriter) Fields {
+	var fields Fields
+	err := json.Unmarshal(<-cw, &fields)
+	assert.Nil(t, err)
+	return fields
+}
+
+func TestLoggerWriterMultipleLines(t *testing.T) {
+	cw := channelWriter(make(chan []byte, 3))
+	log := New()
+	log.Out = cw
+	log.Formatter = new(JSONFormatter)
+
+	w := log.WriterLevel(ErrorLevel)
+	w.Write([]byte("one\ntwo\n"))
+	w.Write([]byte("three\n"))
+
+	for _, msg := range []string{"one", "two", "three"} {
+		fields := readWriterEntry(t, cw)
+		assert.Equal(t, msg, fields["msg"])
+		assert.Equal(t, "error", fields["level"])
+	}
+	w.Close()
+}
+
+func TestWriterFlushesPartialLineOnClose(t *testing.T) {
+	cw := channelWriter(make(chan []byte, 2))
+	log := New()
+	log.Out = cw
+	log.Formatter = new(JSONFormatter)
+
+	w := log.NewModule("http").Writer()
+	w.Write([]byte("complete\npartial"))
+
+	fields := readWriterEntry(t, cw)
+	assert.Equal(t, "complete", fields["msg"])
+	assert.Equal(t, "http", fields[ModuleNameKey])
+
+	w.Close()
+	fields = readWriterEntry(t, cw)
+	assert.Equal(t, "partial", fields["msg"])
+	assert.Equal(t, "info", fields["level"])
+	assert.Equal(t, "http", fields[ModuleNameKey])
+}
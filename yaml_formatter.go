@@ -0,0 +1,240 @@
+package logrus
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultYAMLMaxDepth is the nesting depth used when YAMLBlockFormatter's
+// MaxDepth is not set.
+const DefaultYAMLMaxDepth = 10
+
+// YAMLBlockFormatter formats an entry as a header line with the time, level
+// and message, followed by the fields as an indented YAML block. Nested maps,
+// structs and slices are expanded, which makes it convenient to inspect
+// complex state while debugging locally:
+//
+//    2017-08-22T10:11:12Z info: connected
+//      db:
+//        host: localhost
+//        ports:
+//          - 5432
+//          - 5433
+//
+// The output isn't meant to be parsed back, use the JSONFormatter for that.
+type YAMLBlockFormatter struct {
+	// Disable timestamp logging.
+	DisableTimestamp bool
+
+	// TimestampFormat to use for the header line
+	TimestampFormat string
+
+	// MaxDepth caps how deep nested values are expanded, deeper values are
+	// rendered as `...`. Defaults to DefaultYAMLMaxDepth.
+	MaxDepth int
+}
+
+func (f *YAMLBlockFormatter) Format(entry *Entry) ([]byte, error) {
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	if !f.DisableTimestamp {
+		timestampFormat := f.TimestampFormat
+		if timestampFormat == "" {
+			timestampFormat = DefaultTimestampFormat
+		}
+		b.WriteString(entry.Time.Format(timestampFormat))
+		b.WriteByte(' ')
+	}
+	b.WriteString(entry.Level.String())
+	b.WriteString(": ")
+	b.WriteString(entry.Message)
+	b.WriteByte('\n')
+
+	maxDepth := f.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultYAMLMaxDepth
+	}
+	w := &yamlWriter{b: b, maxDepth: maxDepth, visited: map[uintptr]bool{}}
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		w.writeKeyValue(1, k, reflect.ValueOf(entry.Data[k]))
+	}
+	return b.Bytes(), nil
+}
+
+type yamlWriter struct {
+	b        *bytes.Buffer
+	maxDepth int
+	// pointers being expanded, to detect cycles
+	visited map[uintptr]bool
+}
+
+func (w *yamlWriter) indent(depth int) {
+	for i := 0; i < depth; i++ {
+		w.b.WriteString("  ")
+	}
+}
+
+func (w *yamlWriter) writeKeyValue(depth int, key string, v reflect.Value) {
+	w.indent(depth)
+	w.b.WriteString(yamlString(key))
+	w.b.WriteByte(':')
+	w.writeValue(depth, v)
+}
+
+func (w *yamlWriter) writeItem(depth int, v reflect.Value) {
+	w.indent(depth)
+	w.b.WriteByte('-')
+	w.writeValue(depth, v)
+}
+
+// writeValue writes v after a key or list marker, either inline or as a
+// nested block one level deeper than depth.
+func (w *yamlWriter) writeValue(depth int, v reflect.Value) {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			break
+		}
+		if v.Kind() == reflect.Ptr && isYAMLScalar(v) {
+			break
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if w.visited[ptr] {
+				w.b.WriteString(" <cycle>\n")
+				return
+			}
+			w.visited[ptr] = true
+			defer delete(w.visited, ptr)
+		}
+		v = v.Elem()
+	}
+
+	if isYAMLScalar(v) {
+		w.b.WriteByte(' ')
+		w.writeScalar(depth, v)
+		return
+	}
+	if depth >= w.maxDepth {
+		w.b.WriteString(" ...\n")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Len() == 0 {
+			w.b.WriteString(" {}\n")
+			return
+		}
+		w.b.WriteByte('\n')
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		byName := make(map[string]reflect.Value, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+			byName[names[i]] = v.MapIndex(k)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			w.writeKeyValue(depth+1, name, byName[name])
+		}
+	case reflect.Struct:
+		w.b.WriteByte('\n')
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+			w.writeKeyValue(depth+1, t.Field(i).Name, v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			w.b.WriteString(" []\n")
+			return
+		}
+		w.b.WriteByte('\n')
+		for i := 0; i < v.Len(); i++ {
+			w.writeItem(depth+1, v.Index(i))
+		}
+	}
+}
+
+func (w *yamlWriter) writeScalar(depth int, v reflect.Value) {
+	var s string
+	switch {
+	case !v.IsValid():
+		s = "null"
+	case (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil():
+		s = "null"
+	default:
+		switch value := v.Interface().(type) {
+		case error:
+			s = value.Error()
+		case fmt.Stringer:
+			s = value.String()
+		case string:
+			s = value
+		default:
+			w.b.WriteString(fmt.Sprint(value))
+			w.b.WriteByte('\n')
+			return
+		}
+		if strings.Contains(strings.TrimRight(s, "\n"), "\n") {
+			// multi-line strings, like stacktraces, as literal blocks
+			w.b.WriteString("|\n")
+			for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+				w.indent(depth + 1)
+				w.b.WriteString(line)
+				w.b.WriteByte('\n')
+			}
+			return
+		}
+		s = yamlString(s)
+	}
+	w.b.WriteString(s)
+	w.b.WriteByte('\n')
+}
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+func isYAMLScalar(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	if v.Type().Implements(errorType) || v.Type().Implements(stringerType) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array, reflect.Ptr, reflect.Interface:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+		return (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()
+	}
+	return true
+}
+
+// yamlString quotes s when it could be mistaken for something else than a
+// plain string.
+func yamlString(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n") ||
+		strings.HasPrefix(s, "-") || strings.HasPrefix(s, "?") {
+		return strconv.Quote(s)
+	}
+	return s
+}
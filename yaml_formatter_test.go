@@ -0,0 +1,86 @@
+package logrus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type yamlTestDB struct {
+	Host  string
+	Ports []int
+	Opts  map[string]interface{}
+	inner int
+}
+
+type yamlTestNode struct {
+	Name string
+	Next *yamlTestNode
+}
+
+func TestYAMLBlockFormatterNested(t *testing.T) {
+	formatter := &YAMLBlockFormatter{}
+	entry := WithFields(Fields{
+		"db": &yamlTestDB{
+			Host:  "localhost",
+			Ports: []int{5432, 5433},
+			Opts:  map[string]interface{}{"ssl": true, "timeout": "5s"},
+		},
+		"err":   errors.New("wild walrus"),
+		"empty": []string{},
+		"nil":   nil,
+	})
+	entry.Level = InfoLevel
+	entry.Message = "connected"
+	entry.Time = time.Date(2017, 8, 22, 10, 11, 12, 0, time.UTC)
+
+	b, err := formatter.Format(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, `2017-08-22T10:11:12Z info: connected
+  db:
+    Host: localhost
+    Ports:
+      - 5432
+      - 5433
+    Opts:
+      ssl: true
+      timeout: 5s
+  empty: []
+  err: wild walrus
+  nil: null
+`, string(b))
+}
+
+func TestYAMLBlockFormatterMultilineAndQuoting(t *testing.T) {
+	formatter := &YAMLBlockFormatter{DisableTimestamp: true}
+	entry := WithFields(Fields{
+		StacktraceKey: "main.main()\n\tmain.go:10\n",
+		"url":         "http://localhost:80",
+	})
+	entry.Level = ErrorLevel
+	entry.Message = "failed"
+
+	b, err := formatter.Format(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "error: failed\n  stacktrace: |\n    main.main()\n    \tmain.go:10\n  url: \"http://localhost:80\"\n", string(b))
+}
+
+func TestYAMLBlockFormatterDepthAndCycles(t *testing.T) {
+	node := &yamlTestNode{Name: "a"}
+	node.Next = &yamlTestNode{Name: "b", Next: node}
+
+	formatter := &YAMLBlockFormatter{DisableTimestamp: true}
+	entry := WithField("node", node)
+	entry.Message = "cycle"
+	b, err := formatter.Format(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "panic: cycle\n  node:\n    Name: a\n    Next:\n      Name: b\n      Next: <cycle>\n", string(b))
+
+	formatter.MaxDepth = 2
+	entry = WithField("deep", map[string]interface{}{"a": map[string]interface{}{"b": 1}})
+	b, err = formatter.Format(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "panic: \n  deep:\n    a: ...\n", string(b))
+}